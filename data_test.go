@@ -1,17 +1,40 @@
 package main
 
 import (
+	"context"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/asciidoc"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/engine"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/html"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jira"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markdown"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plaintext"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/slack"
 )
 
+var markdownFormat engine.Format = func() engine.Serializer { return markdown.Serializer{} }
+
+// fixtureFormats maps the extension of an optional expected output file to
+// the format it holds. Markdown output is always checked.
+var fixtureFormats = map[string]engine.Format{
+	".html":     func() engine.Serializer { return html.New(false) },
+	".slack":    func() engine.Serializer { return slack.Serializer{} },
+	".blockkit": func() engine.Serializer { return &slack.BlockKit{} },
+	".jira":     func() engine.Serializer { return jira.Serializer{} },
+	".adoc":     func() engine.Serializer { return asciidoc.Serializer{} },
+	".txt":      func() engine.Serializer { return plaintext.Serializer{} },
+}
+
 func TestFixtures(t *testing.T) {
 	entries, err := os.ReadDir("tests")
 	if err != nil {
@@ -36,6 +59,14 @@ func runFixtureCase(t *testing.T, caseDir string) {
 	inputBytes := mustReadFile(t, filepath.Join(caseDir, "input.json"))
 	root := mustParseJSON(t, inputBytes)
 
+	// plan.json is optional; cases without a baseline plan are exercised only
+	// through their alternative plans.
+	if _, err := os.Stat(filepath.Join(caseDir, "plan.json")); os.IsNotExist(err) {
+		runValidPlans(t, caseDir, root)
+		runInvalidPlans(t, caseDir, root)
+		return
+	}
+
 	expectedPlanBytes := mustReadFile(t, filepath.Join(caseDir, "plan.json"))
 	expectedPlan := mustParsePlan(t, expectedPlanBytes)
 
@@ -49,11 +80,11 @@ func runFixtureCase(t *testing.T, caseDir string) {
 		t.Fatalf("generated plan mismatch\nexpected:\n%s\nactual:\n%s", string(expectedPlanBytes), string(generatedPlanBytes))
 	}
 
-	if err := engine.Validate(root, expectedPlan); err != nil {
+	if err := engine.Validate(context.Background(), root, expectedPlan, engine.Limits{}, markdownFormat); err != nil {
 		t.Fatalf("validate baseline plan: %v", err)
 	}
 
-	rendered, err := engine.Render(root, expectedPlan)
+	rendered, err := engine.Render(context.Background(), root, expectedPlan, engine.Limits{}, markdownFormat)
 	if err != nil {
 		t.Fatalf("render baseline plan: %v", err)
 	}
@@ -62,6 +93,8 @@ func runFixtureCase(t *testing.T, caseDir string) {
 	if normalizeFixtureText(rendered) != normalizeFixtureText(string(expectedOutput)) {
 		t.Fatalf("baseline markdown mismatch\nexpected:\n%s\nactual:\n%s", string(expectedOutput), rendered)
 	}
+	assertStreamMatches(t, root, expectedPlan, markdownFormat, rendered)
+	assertFormats(t, root, expectedPlan, filepath.Join(caseDir, "output"))
 
 	runValidPlans(t, caseDir, root)
 	runInvalidPlans(t, caseDir, root)
@@ -89,11 +122,11 @@ func runValidPlans(t *testing.T, caseDir string, root *jsondoc.Node) {
 			planBytes := mustReadFile(t, filepath.Join(validDir, entry.Name()))
 			parsedPlan := mustParsePlan(t, planBytes)
 
-			if err := engine.Validate(root, parsedPlan); err != nil {
+			if err := engine.Validate(context.Background(), root, parsedPlan, engine.Limits{}, markdownFormat); err != nil {
 				t.Fatalf("validate valid plan: %v", err)
 			}
 
-			rendered, err := engine.Render(root, parsedPlan)
+			rendered, err := engine.Render(context.Background(), root, parsedPlan, engine.Limits{}, markdownFormat)
 			if err != nil {
 				t.Fatalf("render valid plan: %v", err)
 			}
@@ -103,6 +136,8 @@ func runValidPlans(t *testing.T, caseDir string, root *jsondoc.Node) {
 			if normalizeFixtureText(rendered) != normalizeFixtureText(string(expectedOutput)) {
 				t.Fatalf("valid plan markdown mismatch\nexpected:\n%s\nactual:\n%s", string(expectedOutput), rendered)
 			}
+			assertStreamMatches(t, root, parsedPlan, markdownFormat, rendered)
+			assertFormats(t, root, parsedPlan, filepath.Join(validDir, name))
 		})
 	}
 }
@@ -129,7 +164,7 @@ func runInvalidPlans(t *testing.T, caseDir string, root *jsondoc.Node) {
 			planBytes := mustReadFile(t, filepath.Join(invalidDir, entry.Name()))
 			parsedPlan := mustParsePlan(t, planBytes)
 
-			err := engine.Validate(root, parsedPlan)
+			err := engine.Validate(context.Background(), root, parsedPlan, engine.Limits{}, markdownFormat)
 			if err == nil {
 				t.Fatalf("expected validation error")
 			}
@@ -140,13 +175,55 @@ func runInvalidPlans(t *testing.T, caseDir string, root *jsondoc.Node) {
 				t.Fatalf("invalid plan error mismatch\nexpected:\n%s\nactual:\n%s", expectedError, actualError)
 			}
 
-			if _, renderErr := engine.Render(root, parsedPlan); renderErr == nil {
+			if _, renderErr := engine.Render(context.Background(), root, parsedPlan, engine.Limits{}, markdownFormat); renderErr == nil {
 				t.Fatalf("expected render error")
 			}
+			if _, streamErr := engine.Stream(context.Background(), root, parsedPlan, engine.Limits{}, markdownFormat, io.Discard); streamErr == nil {
+				t.Fatalf("expected stream error")
+			}
 		})
 	}
 }
 
+// assertFormats renders the plan in every format that has an expected output
+// file next to the Markdown one, named base plus the format's extension.
+func assertFormats(t *testing.T, root *jsondoc.Node, parsedPlan *plan.Plan, base string) {
+	t.Helper()
+
+	for _, extension := range slices.Sorted(maps.Keys(fixtureFormats)) {
+		expectedPath := base + extension
+		if _, err := os.Stat(expectedPath); os.IsNotExist(err) {
+			continue
+		}
+
+		format := fixtureFormats[extension]
+		rendered, err := engine.Render(context.Background(), root, parsedPlan, engine.Limits{}, format)
+		if err != nil {
+			t.Fatalf("render %s: %v", extension, err)
+		}
+
+		expectedOutput := mustReadFile(t, expectedPath)
+		if normalizeFixtureText(rendered) != normalizeFixtureText(string(expectedOutput)) {
+			t.Fatalf("%s output mismatch\nexpected:\n%s\nactual:\n%s", extension, string(expectedOutput), rendered)
+		}
+		assertStreamMatches(t, root, parsedPlan, format, rendered)
+	}
+}
+
+// assertStreamMatches checks that streaming the plan writes exactly the
+// output that Render returned.
+func assertStreamMatches(t *testing.T, root *jsondoc.Node, parsedPlan *plan.Plan, format engine.Format, rendered string) {
+	t.Helper()
+
+	var streamed strings.Builder
+	if _, err := engine.Stream(context.Background(), root, parsedPlan, engine.Limits{}, format, &streamed); err != nil {
+		t.Fatalf("stream plan: %v", err)
+	}
+	if streamed.String() != rendered {
+		t.Fatalf("streamed output mismatch\nexpected:\n%s\nactual:\n%s", rendered, streamed.String())
+	}
+}
+
 func mustReadFile(t *testing.T, path string) []byte {
 	t.Helper()
 
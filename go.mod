@@ -1,3 +1,7 @@
 module github.com/UnitVectorY-Labs/json2mdplan
 
 go 1.26.0 // GOVERSION
+
+require github.com/mattn/go-runewidth v0.0.30
+
+require github.com/clipperhouse/uax29/v2 v2.2.0 // indirect
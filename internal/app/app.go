@@ -1,15 +1,15 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
-	"github.com/UnitVectorY-Labs/json2mdplan/internal/engine"
-	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
-	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan"
 )
 
 func Run(args []string, stdin io.Reader, stdout io.Writer) error {
@@ -44,17 +44,7 @@ func runPlan(args []string, stdin io.Reader, stdout io.Writer) error {
 		return err
 	}
 
-	root, err := jsondoc.Parse(jsonBytes)
-	if err != nil {
-		return err
-	}
-
-	generatedPlan, err := plan.Generate(root)
-	if err != nil {
-		return err
-	}
-
-	output, err := plan.Marshal(*generatedPlan)
+	output, err := json2mdplan.Generate(context.Background(), jsonBytes, json2mdplan.GenerateOptions{})
 	if err != nil {
 		return err
 	}
@@ -71,6 +61,10 @@ func runRender(args []string, stdin io.Reader, stdout io.Writer) error {
 	inlinePlan := fs.String("plan", "", "")
 	planFile := fs.String("plan-file", "", "")
 	outFile := fs.String("out-file", "", "")
+	outputFormat := fs.String("output-format", string(json2mdplan.FormatMarkdown), "")
+	standalone := fs.Bool("standalone", false, "")
+	elidedFile := fs.String("elided-file", "", "")
+	spoolDir := fs.String("spool-dir", os.TempDir(), "")
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -86,22 +80,43 @@ func runRender(args []string, stdin io.Reader, stdout io.Writer) error {
 		return err
 	}
 
-	root, err := jsondoc.Parse(jsonBytes)
-	if err != nil {
+	var report json2mdplan.Report
+	opts := json2mdplan.RenderOptions{
+		Format:     json2mdplan.Format(*outputFormat),
+		Standalone: *standalone,
+		SpoolDir:   *spoolDir,
+		Report:     &report,
+	}
+	if err := renderOutput(stdout, *outFile, jsonBytes, planBytes, opts); err != nil {
 		return err
 	}
 
-	parsedPlan, err := plan.Parse(planBytes)
-	if err != nil {
-		return err
+	if *elidedFile == "" {
+		return nil
 	}
+	var elided strings.Builder
+	for _, path := range report.Elided {
+		elided.WriteString(path + "\n")
+	}
+	return os.WriteFile(*elidedFile, []byte(elided.String()), 0o644)
+}
 
-	output, err := engine.Render(root, parsedPlan)
-	if err != nil {
-		return err
+// renderOutput renders to stdout, or to outFile when it is set.
+func renderOutput(stdout io.Writer, outFile string, jsonBytes []byte, planBytes []byte, opts json2mdplan.RenderOptions) error {
+	if outFile == "" {
+		return json2mdplan.RenderTo(context.Background(), stdout, jsonBytes, planBytes, opts)
 	}
 
-	return writeOutput(stdout, *outFile, []byte(output))
+	// The file is only created once rendering succeeds, so a failed render
+	// leaves any existing file untouched.
+	out := &lazyFile{path: outFile}
+	if err := json2mdplan.RenderTo(context.Background(), out, jsonBytes, planBytes, opts); err != nil {
+		if out.file != nil {
+			out.file.Close()
+		}
+		return err
+	}
+	return out.Close()
 }
 
 func readJSONInput(stdin io.Reader, inline string, file string) ([]byte, error) {
@@ -149,3 +164,31 @@ func writeOutput(stdout io.Writer, outFile string, data []byte) error {
 
 	return os.WriteFile(outFile, data, 0o644)
 }
+
+// lazyFile creates the file at path on the first write, or on Close when
+// nothing was written.
+type lazyFile struct {
+	path string
+	file *os.File
+}
+
+func (f *lazyFile) Write(p []byte) (int, error) {
+	if f.file == nil {
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+		if err != nil {
+			return 0, err
+		}
+		f.file = file
+	}
+	return f.file.Write(p)
+}
+
+func (f *lazyFile) Close() error {
+	if f.file == nil {
+		_, err := f.Write(nil)
+		if err != nil {
+			return err
+		}
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderWritesNothingOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	// The plan renders title before coverage fails on tags.
+	input := `{"title":"Release","tags":["go","cli"]}`
+	plan := `{"version":1,"directives":[{"op":"paragraph","path":"title"}]}`
+
+	t.Run("stdout", func(t *testing.T) {
+		var stdout bytes.Buffer
+		if err := Run([]string{"render", "--json", input, "--plan", plan}, nil, &stdout); err == nil {
+			t.Fatal("expected render to fail")
+		}
+		if stdout.Len() != 0 {
+			t.Fatalf("expected no output, got %q", stdout.String())
+		}
+	})
+
+	t.Run("new out file", func(t *testing.T) {
+		outFile := filepath.Join(dir, "new.md")
+		if err := Run([]string{"render", "--json", input, "--plan", plan, "--out-file", outFile}, nil, &bytes.Buffer{}); err == nil {
+			t.Fatal("expected render to fail")
+		}
+		if _, err := os.Stat(outFile); !os.IsNotExist(err) {
+			t.Fatalf("expected %s not to be created, got %v", outFile, err)
+		}
+	})
+
+	t.Run("existing out file", func(t *testing.T) {
+		outFile := filepath.Join(dir, "existing.md")
+		if err := os.WriteFile(outFile, []byte("previous\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Run([]string{"render", "--json", input, "--plan", plan, "--out-file", outFile}, nil, &bytes.Buffer{}); err == nil {
+			t.Fatal("expected render to fail")
+		}
+		data, err := os.ReadFile(outFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "previous\n" {
+			t.Fatalf("expected %s to be untouched, got %q", outFile, data)
+		}
+	})
+}
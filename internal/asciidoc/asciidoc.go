@@ -0,0 +1,275 @@
+// Package asciidoc serializes an ast.Document as AsciiDoc. Values that
+// contain characters AsciiDoc would interpret are written as passthroughs,
+// and links are only written for URLs with a safe scheme.
+package asciidoc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// reserved lists the characters percent-encoded in URLs because they would
+// end a link or an image macro.
+const reserved = "[] "
+
+// plainText matches the characters of values written as is. Anything else is
+// written as a passthrough.
+var plainText = regexp.MustCompile(`^[\p{L}\p{N} .,;:!?%/@=-]*$`)
+
+// invalidAttribute matches the characters not allowed in a document
+// attribute name.
+var invalidAttribute = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// Serializer writes blocks as AsciiDoc. Front matter becomes document
+// attributes.
+type Serializer struct{}
+
+func (Serializer) Block(block ast.Block) []string {
+	return Block(block)
+}
+
+func (Serializer) End() ([]string, error) {
+	return nil, nil
+}
+
+// Block returns the AsciiDoc lines of one block.
+func Block(block ast.Block) []string {
+	switch b := block.(type) {
+	case *ast.Heading:
+		return headingLines(b)
+	case *ast.Paragraph:
+		return strings.Split(inlines(b.Inlines), "\n")
+	case *ast.List:
+		return listLines(b, 1)
+	case *ast.Table:
+		return tableLines(b)
+	case *ast.CodeBlock:
+		return codeLines(b)
+	case *ast.BlockQuote:
+		return wrap("____", markup.BlockLines(b.Blocks, Block))
+	case *ast.Alert:
+		return append([]string{"[" + b.Kind + "]"}, wrap("====", markup.BlockLines(b.Blocks, Block))...)
+	case *ast.Details:
+		lines := []string{"." + escape(b.Summary), "[%collapsible]"}
+		return append(lines, wrap("====", markup.BlockLines(b.Blocks, Block))...)
+	case *ast.DefinitionList:
+		lines := make([]string, 0, len(b.Items))
+		for _, item := range b.Items {
+			lines = append(lines, escape(item.Term)+":: "+inlines(item.Inlines))
+		}
+		return lines
+	case *ast.ThematicBreak:
+		return []string{"'''"}
+	case *ast.FrontMatter:
+		return attributeLines(b)
+	case *ast.RawBlock:
+		lines := make([]string, 0, len(b.Lines))
+		for _, line := range b.Lines {
+			lines = append(lines, escape(line))
+		}
+		return lines
+	default:
+		panic(fmt.Sprintf("asciidoc: unsupported block %T", block))
+	}
+}
+
+// headingLines writes a section title. A single "=" is the document title,
+// so levels start at "==". AsciiDoc has five section levels, one fewer than
+// Markdown, so a level 6 heading is written as a bold line instead.
+func headingLines(heading *ast.Heading) []string {
+	title := singleLine(heading.Inlines)
+	if heading.Level > 5 {
+		if title == "" {
+			return nil
+		}
+		return []string{"**" + title + "**"}
+	}
+	return []string{strings.Repeat("=", heading.Level+1) + " " + title}
+}
+
+// listLines writes one bullet per item, with one asterisk per level of
+// nesting. Other content beneath an item is attached with a list
+// continuation.
+func listLines(list *ast.List, level int) []string {
+	marker := strings.Repeat("*", level)
+
+	lines := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		lines = append(lines, marker+" "+inlines(item.Inlines))
+		for _, block := range item.Blocks {
+			if nested, ok := block.(*ast.List); ok {
+				lines = append(lines, listLines(nested, level+1)...)
+				continue
+			}
+			lines = append(lines, "+")
+			lines = append(lines, Block(block)...)
+		}
+	}
+	return lines
+}
+
+func tableLines(table *ast.Table) []string {
+	attributes := `[options="header"]`
+	if table.Align != nil {
+		cols := make([]string, len(table.Align))
+		for i, align := range table.Align {
+			switch align {
+			case ast.AlignLeft:
+				cols[i] = "<"
+			case ast.AlignCenter:
+				cols[i] = "^"
+			case ast.AlignRight:
+				cols[i] = ">"
+			default:
+				cols[i] = "1"
+			}
+		}
+		attributes = `[cols="` + strings.Join(cols, ",") + `",options="header"]`
+	}
+
+	lines := make([]string, 0, len(table.Rows)+4)
+	lines = append(lines, attributes, "|===", tableRow(table.Header))
+	for _, row := range table.Rows {
+		lines = append(lines, tableRow(row))
+	}
+	return append(lines, "|===")
+}
+
+func tableRow(cells []*ast.Cell) string {
+	values := make([]string, len(cells))
+	for i, cell := range cells {
+		values[i] = "|" + strings.ReplaceAll(inlines(cell.Inlines), "|", `\|`)
+	}
+	return strings.Join(values, " ")
+}
+
+// codeLines writes a listing block, tagged as source when the language is
+// known. The delimiter is lengthened until no line of the content matches it.
+func codeLines(block *ast.CodeBlock) []string {
+	content := strings.ReplaceAll(block.Content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	lines := strings.Split(content, "\n")
+
+	delimiter := "----"
+	for slices.Contains(lines, delimiter) {
+		delimiter += "-"
+	}
+
+	code := wrap(delimiter, lines)
+	if block.Language != "" {
+		code = append([]string{"[source," + block.Language + "]"}, code...)
+	}
+	return code
+}
+
+// attributeLines writes front matter as document attribute entries. Keys are
+// lowercased with runs of other characters replaced by a hyphen.
+func attributeLines(frontMatter *ast.FrontMatter) []string {
+	lines := make([]string, 0, len(frontMatter.Fields))
+	for _, field := range frontMatter.Fields {
+		name := strings.Trim(invalidAttribute.ReplaceAllString(strings.ToLower(field.Key), "-"), "-")
+		if name == "" {
+			name = "attribute"
+		}
+		value := strings.ReplaceAll(field.Value, "\n", " ")
+		lines = append(lines, strings.TrimRight(":"+name+": "+value, " "))
+	}
+	return lines
+}
+
+func wrap(delimiter string, lines []string) []string {
+	return append(append([]string{delimiter}, lines...), delimiter)
+}
+
+func inlines(inlines []ast.Inline) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		b.WriteString(inlineAsciiDoc(inline))
+	}
+	return b.String()
+}
+
+// singleLine returns inline content that must fit on one line, such as a
+// heading, with line breaks written as spaces.
+func singleLine(content []ast.Inline) string {
+	return strings.ReplaceAll(inlines(content), " +\n", " ")
+}
+
+func inlineAsciiDoc(inline ast.Inline) string {
+	switch n := inline.(type) {
+	case *ast.Text:
+		return escape(n.Value)
+	case *ast.Raw:
+		return escape(n.Value)
+	case *ast.Strong:
+		text := inlines(n.Inlines)
+		if text == "" {
+			return ""
+		}
+		return "**" + text + "**"
+	case *ast.Code:
+		if n.Value == "" {
+			return ""
+		}
+		return "``" + escape(n.Value) + "``"
+	case *ast.Link:
+		// Relative URLs and fragments are not written as links, because
+		// AsciiDoc anchors differ from Markdown anchors.
+		target, ok := markup.LinkURL(n.URL, reserved)
+		if !ok {
+			return inlines(n.Inlines)
+		}
+		return "link:" + target + "[" + singleLine(n.Inlines) + "]"
+	case *ast.Image:
+		target, ok := markup.ImageURL(n.URL, reserved)
+		if !ok {
+			return escape(n.Alt)
+		}
+		return "image:" + target + "[" + escapeAttribute(n.Alt) + "]"
+	case *ast.LineBreak:
+		return " +\n"
+	default:
+		panic(fmt.Sprintf("asciidoc: unsupported inline %T", inline))
+	}
+}
+
+// escapeAttribute returns an image's alternative text as a quoted attribute
+// when it contains a character that would end or split the attribute list.
+func escapeAttribute(value string) string {
+	if !strings.ContainsAny(value, `],"=`) {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// escape returns each line of value as is when it holds only plain text, and
+// as a passthrough otherwise. Passthroughs still escape HTML.
+func escape(value string) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		switch {
+		case isPlain(line):
+		case !strings.Contains(line, "+"):
+			lines[i] = "++" + line + "++"
+		default:
+			lines[i] = "pass:c[" + strings.ReplaceAll(line, "]", `\]`) + "]"
+		}
+	}
+	return strings.Join(lines, " +\n")
+}
+
+// isPlain reports whether line can be written as is, wherever it appears.
+func isPlain(line string) bool {
+	if strings.Contains(line, "--") || strings.Contains(line, "..") {
+		return false
+	}
+	if line != "" && strings.ContainsRune(".-:=", rune(line[0])) {
+		return false
+	}
+	return plainText.MatchString(line)
+}
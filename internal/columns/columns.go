@@ -0,0 +1,124 @@
+// Package columns lays out table cells as plain text columns, padded to the
+// width at which a terminal displays them.
+package columns
+
+import (
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+	"github.com/mattn/go-runewidth"
+)
+
+// condition measures characters as a terminal outside a CJK locale does.
+// It is fixed rather than read from the environment, so that output does not
+// depend on where it is rendered.
+var condition = &runewidth.Condition{}
+
+// tabWidth is the distance between tab stops.
+const tabWidth = 8
+
+// Width returns the number of terminal columns value occupies. Wide
+// characters and emoji, including flags and joined sequences, take two
+// columns, and combining marks, format characters, and control characters
+// take none. A tab advances to the next tab stop.
+func Width(value string) int {
+	width := 0
+	for i, segment := range strings.Split(value, "\t") {
+		if i > 0 {
+			width += tabWidth - width%tabWidth
+		}
+		width += condition.StringWidth(segment)
+	}
+	return width
+}
+
+// expandTabs replaces each tab in value with the spaces up to the next tab
+// stop, so that a cell is displayed the same wherever its column starts.
+func expandTabs(value string) string {
+	if !strings.Contains(value, "\t") {
+		return value
+	}
+
+	var b strings.Builder
+	for i, segment := range strings.Split(value, "\t") {
+		if i > 0 {
+			width := Width(b.String())
+			b.WriteString(strings.Repeat(" ", tabWidth-width%tabWidth))
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+// Lines returns the header and rows as columns padded to a common width and
+// separated by two spaces, with a dashed rule below the header. Body cells
+// are aligned as align gives, which may be nil; header cells are left
+// aligned. Cells must not contain line breaks.
+func Lines(header []string, rows [][]string, align []ast.Alignment) []string {
+	header = expandCells(header)
+	expanded := make([][]string, len(rows))
+	for i, row := range rows {
+		expanded[i] = expandCells(row)
+	}
+	rows = expanded
+
+	widths := make([]int, len(header))
+	for i, cell := range header {
+		widths[i] = Width(cell)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], Width(cell))
+			}
+		}
+	}
+
+	rule := make([]string, len(widths))
+	for i, width := range widths {
+		rule[i] = strings.Repeat("-", width)
+	}
+
+	lines := make([]string, 0, len(rows)+2)
+	lines = append(lines, line(header, widths, nil), strings.Join(rule, "  "))
+	for _, row := range rows {
+		lines = append(lines, line(row, widths, align))
+	}
+	return lines
+}
+
+func expandCells(cells []string) []string {
+	expanded := make([]string, len(cells))
+	for i, cell := range cells {
+		expanded[i] = expandTabs(cell)
+	}
+	return expanded
+}
+
+func line(row []string, widths []int, align []ast.Alignment) string {
+	cells := make([]string, len(widths))
+	for i, width := range widths {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		alignment := ast.AlignDefault
+		if i < len(align) {
+			alignment = align[i]
+		}
+		cells[i] = pad(cell, width, alignment)
+	}
+	return strings.TrimRight(strings.Join(cells, "  "), " ")
+}
+
+func pad(value string, width int, align ast.Alignment) string {
+	space := width - Width(value)
+	switch align {
+	case ast.AlignRight:
+		return strings.Repeat(" ", space) + value
+	case ast.AlignCenter:
+		return strings.Repeat(" ", space/2) + value + strings.Repeat(" ", space-space/2)
+	default:
+		return value + strings.Repeat(" ", space)
+	}
+}
@@ -0,0 +1,140 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// alertKinds lists the GitHub alert kinds in the order GitHub documents them.
+var alertKinds = []string{"NOTE", "TIP", "IMPORTANT", "WARNING", "CAUTION"}
+
+type alertHandler struct{}
+
+func (alertHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+	if directive.Kind == "" && directive.KindPath == "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "one of kind or kind_path is required")
+	}
+	if directive.Kind != "" && !isAlertKind(directive.Kind) {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("kind %q is not one of %s", directive.Kind, strings.Join(alertKinds, ", ")))
+	}
+	if directive.KindPath == "" && len(directive.Kinds) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "kinds requires kind_path")
+	}
+	if directive.KindPath != "" && len(directive.Kinds) == 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "kind_path requires kinds")
+	}
+	for _, value := range slices.Sorted(maps.Keys(directive.Kinds)) {
+		if !isAlertKind(directive.Kinds[value]) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("kinds value %q is not one of %s", directive.Kinds[value], strings.Join(alertKinds, ", ")))
+		}
+	}
+
+	target, absolutePath, err := resolvePath(root, directiveIndex, directive.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	consumed := make([]string, 0)
+	body := make([]ast.Block, 0)
+
+	switch {
+	case target.Kind == jsondoc.Array:
+		list := &ast.List{}
+		for index, item := range target.Array {
+			if !item.IsScalar() {
+				return nil, diagnostics.New(
+					"non_scalar_item",
+					directiveIndex,
+					directive.Path,
+					"directive %q requires all array items at path %q to be scalar values",
+					directive.Op,
+					displayPath(directive.Path),
+				)
+			}
+
+			value, err := item.FormatScalar()
+			if err != nil {
+				return nil, err
+			}
+
+			list.Items = append(list.Items, &ast.ListItem{Inlines: []ast.Inline{raw(value)}})
+			consumed = append(consumed, absolutePath+"/"+strconv.Itoa(index))
+		}
+		if directive.Label != "" {
+			body = append(body, &ast.Paragraph{Inlines: []ast.Inline{lead(directive.Label)}})
+		}
+		if len(list.Items) > 0 {
+			body = append(body, list)
+		}
+	case target.IsScalar():
+		value, err := target.FormatScalar()
+		if err != nil {
+			return nil, err
+		}
+
+		body = proseBlocks(directive.Label, value, raw)
+		consumed = append(consumed, absolutePath)
+	default:
+		return nil, diagnostics.New(
+			"type_mismatch",
+			directiveIndex,
+			directive.Path,
+			"directive %q requires path %q to resolve to a scalar or an array of scalars",
+			directive.Op,
+			displayPath(directive.Path),
+		)
+	}
+
+	kind := strings.ToUpper(directive.Kind)
+	if directive.KindPath != "" {
+		kindNode, kindPath, err := resolvePath(root, directiveIndex, directive.KindPath)
+		if err != nil {
+			return nil, err
+		}
+		if !kindNode.IsScalar() {
+			return nil, nonScalarFieldError(directiveIndex, directive.KindPath)
+		}
+
+		value, err := kindNode.FormatScalar()
+		if err != nil {
+			return nil, err
+		}
+
+		// A value that falls back to the default kind is not written, so it is
+		// left for coverage to report unless another directive renders it.
+		if mapped, ok := directive.Kinds[value]; ok {
+			kind = strings.ToUpper(mapped)
+			consumed = append(consumed, kindPath)
+		} else if kind == "" {
+			return nil, diagnostics.New(
+				"unmapped_value",
+				directiveIndex,
+				directive.KindPath,
+				"value %q at path %q has no entry in kinds and no default kind is set",
+				value,
+				directive.KindPath,
+			)
+		}
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.Alert{Kind: kind, Blocks: body}},
+		Consumed: consumed,
+	}, nil
+}
+
+func isAlertKind(kind string) bool {
+	return slices.Contains(alertKinds, strings.ToUpper(kind))
+}
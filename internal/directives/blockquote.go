@@ -0,0 +1,27 @@
+package directives
+
+import (
+	"context"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type blockquoteHandler struct{}
+
+func (blockquoteHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+
+	target, absolutePath, err := requirePath(root, directiveIndex, directive.Path, jsondoc.String, directive.Op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.BlockQuote{Blocks: proseBlocks(directive.Label, target.String, raw)}},
+		Consumed: []string{absolutePath},
+	}, nil
+}
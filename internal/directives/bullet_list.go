@@ -1,31 +1,42 @@
 package directives
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
 )
 
 type bulletListHandler struct{}
 
-func (bulletListHandler) Execute(root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+func (bulletListHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
 	if len(directive.Fields) > 0 {
 		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
 	}
 
+	if err := validateWindow(directiveIndex, directive); err != nil {
+		return nil, err
+	}
+
 	target, absolutePath, err := requirePath(root, directiveIndex, directive.Path, jsondoc.Array, directive.Op)
 	if err != nil {
 		return nil, err
 	}
+	targetTokens, err := jsondoc.PointerTokens(absolutePath)
+	if err != nil {
+		return nil, err
+	}
 
-	lines := make([]string, 0, len(target.Array))
-	consumed := make([]string, 0, len(target.Array))
+	start, end := window(directive, len(target.Array))
+	list := &ast.List{Items: make([]*ast.ListItem, 0, end-start+1)}
+	consumed := make([]string, 0, end-start)
 
-	for index, item := range target.Array {
+	checkItem := func(item *jsondoc.Node, tokens []string) error {
 		if !item.IsScalar() {
-			return nil, diagnostics.New(
+			return diagnostics.New(
 				"non_scalar_item",
 				directiveIndex,
 				directive.Path,
@@ -34,18 +45,36 @@ func (bulletListHandler) Execute(root *jsondoc.Node, directiveIndex int, directi
 				displayPath(directive.Path),
 			)
 		}
+		return nil
+	}
+
+	for index := start; index < end; index++ {
+		item := target.Array[index]
+		if err := checkItem(item, nil); err != nil {
+			return nil, err
+		}
 
 		value, err := item.FormatScalar()
 		if err != nil {
 			return nil, err
 		}
 
-		lines = append(lines, formatBullet(value))
+		list.Items = append(list.Items, &ast.ListItem{Inlines: []ast.Inline{raw(value)}})
 		consumed = append(consumed, absolutePath+"/"+strconv.Itoa(index))
 	}
 
+	if more := formatMore(directive, len(target.Array)-end); more != "" {
+		list.Items = append(list.Items, &ast.ListItem{Inlines: []ast.Inline{text(more)}})
+	}
+
+	elided, err := elideItems(target.Array, targetTokens, start, end, checkItem)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Result{
-		Lines:    lines,
+		Blocks:   []ast.Block{list},
 		Consumed: consumed,
+		Elided:   elided,
 	}, nil
 }
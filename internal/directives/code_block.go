@@ -0,0 +1,31 @@
+package directives
+
+import (
+	"context"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type codeBlockHandler struct{}
+
+func (codeBlockHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+	if strings.ContainsAny(directive.Language, " \t\r\n`") {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "language must be a single word without backticks")
+	}
+
+	target, absolutePath, err := requirePath(root, directiveIndex, directive.Path, jsondoc.String, directive.Op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.CodeBlock{Language: directive.Language, Content: target.String}},
+		Consumed: []string{absolutePath},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package directives
+
+import (
+	"context"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type detailsHandler struct{}
+
+func (detailsHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+	if directive.Path != "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "path is not supported; child directives resolve their own paths")
+	}
+	if (directive.Summary == "") == (directive.SummaryPath == "") {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "exactly one of summary or summary_path is required")
+	}
+	if len(directive.Directives) == 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "directives must not be empty")
+	}
+
+	consumed := make([]string, 0)
+	elided := make([]string, 0)
+
+	summary := directive.Summary
+	if directive.SummaryPath != "" {
+		node, absolutePath, err := resolvePath(root, directiveIndex, directive.SummaryPath)
+		if err != nil {
+			return nil, err
+		}
+		if !node.IsScalar() {
+			return nil, nonScalarFieldError(directiveIndex, directive.SummaryPath)
+		}
+
+		summary, err = node.FormatScalar()
+		if err != nil {
+			return nil, err
+		}
+		consumed = append(consumed, absolutePath)
+	}
+
+	results := make([]*Result, 0, len(directive.Directives))
+	for _, child := range directive.Directives {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if child.Op == "front_matter" {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "front_matter cannot be nested")
+		}
+
+		result, err := Execute(ctx, root, directiveIndex, child)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+		consumed = append(consumed, result.Consumed...)
+		elided = append(elided, result.Elided...)
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.Details{Summary: summary, Blocks: Assemble(results)}},
+		Consumed: consumed,
+		Elided:   elided,
+	}, nil
+}
@@ -1,29 +1,90 @@
 package directives
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
 )
 
 type Result struct {
-	Lines    []string
+	Blocks   []ast.Block
 	Consumed []string
+
+	// Elided lists the leaf paths a directive deliberately left out of its
+	// output, such as items beyond a limit. They count as covered.
+	Elided []string
+
+	// Finalize, when set, replaces Blocks once every directive has been
+	// evaluated. It receives the output of the directives before and after
+	// it, for content such as a table of contents that depends on them.
+	Finalize func(preceding []ast.Block, following []ast.Block) []ast.Block
 }
 
 type Handler interface {
-	Execute(root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error)
+	Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error)
 }
 
+var handlersMu sync.RWMutex
+
 var handlers = map[string]Handler{
-	"bullet_list":   bulletListHandler{},
-	"named_bullets": namedBulletsHandler{},
+	"alert":          alertHandler{},
+	"blockquote":     blockquoteHandler{},
+	"bullet_list":    bulletListHandler{},
+	"code_block":     codeBlockHandler{},
+	"details":        detailsHandler{},
+	"front_matter":   frontMatterHandler{},
+	"image":          imageHandler{},
+	"json_block":     jsonBlockHandler{},
+	"link":           linkHandler{},
+	"named_bullets":  namedBulletsHandler{},
+	"nested_bullets": nestedBulletsHandler{},
+	"paragraph":      paragraphHandler{},
+	"rule":           ruleHandler{},
+	"table":          tableHandler{},
+	"text":           textHandler{},
+	"toc":            tocHandler{},
+}
+
+// documentHandler is implemented by handlers whose output is computed from the
+// rest of the document through Result.Finalize.
+type documentHandler interface {
+	needsDocument()
+}
+
+// NeedsDocument reports whether the op's output depends on the output of the
+// other directives, so the document cannot be written until every directive
+// has been evaluated.
+func NeedsDocument(op string) bool {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+
+	_, ok := handlers[op].(documentHandler)
+	return ok
 }
 
-func Execute(root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+// Register adds a handler for a custom op. It fails when the op is already
+// handled, including by a built-in directive.
+func Register(op string, handler Handler) error {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+
+	if _, ok := handlers[op]; ok {
+		return fmt.Errorf("directive %q is already registered", op)
+	}
+	handlers[op] = handler
+	return nil
+}
+
+func Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	handlersMu.RLock()
 	handler, ok := handlers[directive.Op]
+	handlersMu.RUnlock()
 	if !ok {
 		return nil, diagnostics.New(
 			"unknown_directive",
@@ -33,11 +94,14 @@ func Execute(root *jsondoc.Node, directiveIndex int, directive plan.Directive) (
 			directive.Op,
 		)
 	}
+	if err := checkOptions(directiveIndex, directive); err != nil {
+		return nil, err
+	}
 
-	return handler.Execute(root, directiveIndex, directive)
+	return handler.Execute(ctx, root, directiveIndex, directive)
 }
 
-func requirePath(root *jsondoc.Node, directiveIndex int, expr string, expected jsondoc.Kind, op string) (*jsondoc.Node, string, error) {
+func resolvePath(root *jsondoc.Node, directiveIndex int, expr string) (*jsondoc.Node, string, error) {
 	node, absolutePath, err := jsondoc.Resolve(root, root, nil, expr)
 	if err != nil {
 		return nil, "", diagnostics.New(
@@ -50,6 +114,15 @@ func requirePath(root *jsondoc.Node, directiveIndex int, expr string, expected j
 		)
 	}
 
+	return node, absolutePath, nil
+}
+
+func requirePath(root *jsondoc.Node, directiveIndex int, expr string, expected jsondoc.Kind, op string) (*jsondoc.Node, string, error) {
+	node, absolutePath, err := resolvePath(root, directiveIndex, expr)
+	if err != nil {
+		return nil, "", err
+	}
+
 	if node.Kind != expected {
 		return nil, "", diagnostics.New(
 			"type_mismatch",
@@ -104,6 +177,36 @@ func unexpectedPlanShape(directiveIndex int, path string, op string, problem str
 	)
 }
 
-func formatBullet(value string) string {
-	return fmt.Sprintf("- %s", value)
+// Assemble joins the output of consecutive directives into the final blocks,
+// applying any Finalize step against the output that follows it.
+func Assemble(results []*Result) []ast.Block {
+	following := make([]ast.Block, 0)
+	for i := len(results) - 1; i >= 0; i-- {
+		blocks := results[i].Blocks
+		if results[i].Finalize != nil {
+			preceding := make([]ast.Block, 0)
+			for _, earlier := range results[:i] {
+				preceding = append(preceding, earlier.Blocks...)
+			}
+			blocks = results[i].Finalize(preceding, following)
+		}
+		following = append(slices.Clone(blocks), following...)
+	}
+	return following
+}
+
+// raw returns a JSON value or label as inline Markdown source, so Markdown
+// output passes any formatting it contains through unchanged.
+func raw(value string) ast.Inline {
+	return &ast.Raw{Value: value}
+}
+
+// text returns literal text that every output format escapes.
+func text(value string) ast.Inline {
+	return &ast.Text{Value: value}
+}
+
+// lead returns the bold "label:" that introduces a value.
+func lead(label string) ast.Inline {
+	return &ast.Strong{Inlines: []ast.Inline{raw(label + ":")}}
 }
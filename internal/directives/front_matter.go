@@ -0,0 +1,83 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type frontMatterHandler struct{}
+
+func (frontMatterHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if directiveIndex != 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "must be the first directive in the plan")
+	}
+	if len(directive.Fields) == 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields must not be empty")
+	}
+
+	target, targetPath, err := resolvePath(root, directiveIndex, directive.Path)
+	if err != nil {
+		return nil, err
+	}
+	targetTokens, err := jsondoc.PointerTokens(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	frontMatter := &ast.FrontMatter{Fields: make([]*ast.MetadataField, 0, len(directive.Fields))}
+	consumed := make([]string, 0, len(directive.Fields))
+	keys := make([]string, 0, len(directive.Fields))
+
+	for _, field := range directive.Fields {
+		if field.Label == "" {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "field labels must not be empty")
+		}
+		if slices.Contains(keys, field.Label) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("field label %q is used more than once", field.Label))
+		}
+		keys = append(keys, field.Label)
+
+		hasPath := field.Path != "" && field.Path != "."
+		if hasPath == (len(field.Value) > 0) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "each field requires exactly one of path or value")
+		}
+
+		var node *jsondoc.Node
+		if hasPath {
+			var absolutePath string
+			node, absolutePath, err = jsondoc.Resolve(root, target, targetTokens, field.Path)
+			if err != nil {
+				return nil, missingFieldError(directiveIndex, field.Path)
+			}
+			if !node.IsScalar() {
+				return nil, nonScalarFieldError(directiveIndex, field.Path)
+			}
+			consumed = append(consumed, absolutePath)
+		} else {
+			node, err = jsondoc.Parse(field.Value)
+			if err != nil || !node.IsScalar() {
+				return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("value for field %q must be a JSON scalar", field.Label))
+			}
+		}
+
+		value, err := node.FormatScalar()
+		if err != nil {
+			return nil, err
+		}
+		frontMatter.Fields = append(frontMatter.Fields, &ast.MetadataField{
+			Key:      field.Label,
+			Value:    value,
+			IsString: node.Kind == jsondoc.String,
+		})
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{frontMatter},
+		Consumed: consumed,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package directives
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+var groupOrders = []string{"", "first_seen", "sorted"}
+
+// lineBreaks collapses the line breaks in a value written on one line.
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// elementGroup is the set of array items that share one group_by value.
+// Members holds positions in the slice passed to groupElements.
+type elementGroup struct {
+	Key     *jsondoc.Node
+	Members []int
+}
+
+// validateGrouping checks the group_by options shared by every directive that
+// supports grouping.
+func validateGrouping(directiveIndex int, directive plan.Directive) error {
+	if !slices.Contains(groupOrders, directive.GroupOrder) {
+		return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("group_order %q is not one of first_seen, sorted", directive.GroupOrder))
+	}
+	if directive.GroupBy == "" && (directive.GroupOrder != "" || directive.HeadingLevel != 0) {
+		return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "group_order and heading_level require group_by")
+	}
+	if directive.HeadingLevel < 0 || directive.HeadingLevel > 6 {
+		return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "heading_level must be between 1 and 6")
+	}
+	return nil
+}
+
+// groupElements partitions elements by the scalar at group_by, resolved
+// relative to each element. Groups are returned in first-seen order, or sorted
+// by value when group_order is sorted. The group_by value of every element is
+// returned as consumed.
+func groupElements(root *jsondoc.Node, directiveIndex int, directive plan.Directive, elements []*jsondoc.Node, tokens [][]string) ([]elementGroup, []string, error) {
+	groups := make([]elementGroup, 0)
+	positions := make(map[string]int)
+	consumed := make([]string, 0, len(elements))
+
+	for i, element := range elements {
+		node, absolutePath, err := groupKey(root, directiveIndex, directive, element, tokens[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		consumed = append(consumed, absolutePath)
+
+		// Values of different kinds never share a group, so 1 and "1" stay
+		// apart.
+		value, err := node.FormatScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := string(node.Kind) + ":" + value
+
+		position, ok := positions[key]
+		if !ok {
+			position = len(groups)
+			positions[key] = position
+			groups = append(groups, elementGroup{Key: node})
+		}
+		groups[position].Members = append(groups[position].Members, i)
+	}
+
+	if directive.GroupOrder == "sorted" {
+		slices.SortStableFunc(groups, func(a, b elementGroup) int {
+			return compareScalars(a.Key, b.Key)
+		})
+	}
+
+	return groups, consumed, nil
+}
+
+// groupKey resolves the scalar at group_by relative to element.
+func groupKey(root *jsondoc.Node, directiveIndex int, directive plan.Directive, element *jsondoc.Node, tokens []string) (*jsondoc.Node, string, error) {
+	node, absolutePath, err := jsondoc.Resolve(root, element, tokens, directive.GroupBy)
+	if err != nil {
+		return nil, "", missingFieldError(directiveIndex, directive.GroupBy)
+	}
+	if !node.IsScalar() {
+		return nil, "", nonScalarFieldError(directiveIndex, directive.GroupBy)
+	}
+	return node, absolutePath, nil
+}
+
+// groupHeading returns the heading written before each group. A heading is a
+// single line, so line breaks in the group value become spaces.
+func groupHeading(directive plan.Directive, key *jsondoc.Node) ast.Block {
+	level := directive.HeadingLevel
+	if level == 0 {
+		level = 2
+	}
+
+	value, _ := key.FormatScalar()
+	value = lineBreaks.Replace(value)
+	return &ast.Heading{Level: level, Inlines: []ast.Inline{text(value)}}
+}
@@ -0,0 +1,41 @@
+package directives
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+)
+
+const defaultSeparator = ", "
+
+// joinScalarArray fills entry with the items of an array of scalars, to be
+// rendered inline and joined by the field separator, and returns the pointer
+// of every item.
+func joinScalarArray(directiveIndex int, field plan.Field, node *jsondoc.Node, absolutePath string, entry *labelledValue) ([]string, error) {
+	separator := field.Separator
+	if separator == "" {
+		separator = defaultSeparator
+	}
+
+	items := make([]string, 0, len(node.Array))
+	consumed := make([]string, 0, len(node.Array))
+	for index, item := range node.Array {
+		if !item.IsScalar() {
+			return nil, nonScalarFieldError(directiveIndex, field.Path)
+		}
+
+		value, err := item.FormatScalar()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+		consumed = append(consumed, absolutePath+"/"+strconv.Itoa(index))
+	}
+
+	entry.Items = items
+	entry.Separator = separator
+	entry.Value = strings.Join(items, separator)
+	return consumed, nil
+}
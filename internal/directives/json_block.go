@@ -0,0 +1,45 @@
+package directives
+
+import (
+	"context"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type jsonBlockHandler struct{}
+
+func (jsonBlockHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+	if strings.ContainsAny(directive.Language, " \t\r\n`") {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "language must be a single word without backticks")
+	}
+
+	target, absolutePath, err := resolvePath(root, directiveIndex, directive.Path)
+	if err != nil {
+		return nil, err
+	}
+	targetTokens, err := jsondoc.PointerTokens(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := target.MarshalIndent("  ")
+	if err != nil {
+		return nil, err
+	}
+
+	language := directive.Language
+	if language == "" {
+		language = "json"
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.CodeBlock{Language: language, Content: string(encoded)}},
+		Consumed: target.LeafPaths(targetTokens),
+	}, nil
+}
@@ -0,0 +1,123 @@
+package directives
+
+import "github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+
+// labelledValue is one label and scalar value pair rendered by a field-based
+// directive. URL is set when the value links to a sibling URL field. Items is
+// set when the value is an array rendered inline, in which case Value holds
+// the items joined by Separator and Code applies to each item.
+type labelledValue struct {
+	Label     string
+	Value     string
+	URL       string
+	Code      bool
+	Items     []string
+	Separator string
+}
+
+// labelledLayouts maps each supported layout name to the function that
+// renders a list of labelled values. The empty name selects the default.
+var labelledLayouts = map[string]func(entries []labelledValue) []ast.Block{
+	"":                bulletLayout,
+	"bullets":         bulletLayout,
+	"table":           tableLayout,
+	"definition_list": definitionListLayout,
+	"stacked":         stackedLayout,
+}
+
+func bulletLayout(entries []labelledValue) []ast.Block {
+	list := &ast.List{Items: make([]*ast.ListItem, 0, len(entries))}
+	for _, entry := range entries {
+		// An empty value leaves the label on its own, with no trailing space.
+		inlines := []ast.Inline{lead(entry.Label)}
+		if entry.Value != "" || entry.URL != "" {
+			inlines = append(inlines, raw(" "))
+			inlines = append(inlines, entry.inlines()...)
+		}
+		list.Items = append(list.Items, &ast.ListItem{Inlines: inlines})
+	}
+	return []ast.Block{list}
+}
+
+func tableLayout(entries []labelledValue) []ast.Block {
+	table := &ast.Table{
+		Header: []*ast.Cell{cell(raw("Field")), cell(raw("Value"))},
+		Rows:   make([][]*ast.Cell, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		table.Rows = append(table.Rows, []*ast.Cell{cell(raw(entry.Label)), cell(entry.inlines()...)})
+	}
+	return []ast.Block{table}
+}
+
+func definitionListLayout(entries []labelledValue) []ast.Block {
+	list := &ast.DefinitionList{Items: make([]*ast.Definition, 0, len(entries))}
+	for _, entry := range entries {
+		list.Items = append(list.Items, &ast.Definition{Term: entry.Label, Inlines: entry.inlines()})
+	}
+	return []ast.Block{list}
+}
+
+func stackedLayout(entries []labelledValue) []ast.Block {
+	blocks := make([]ast.Block, 0, len(entries))
+	for _, entry := range entries {
+		// The label ends with a hard line break so the value starts on the
+		// next line of the same paragraph. Plain values keep their paragraph
+		// breaks.
+		var value []ast.Block
+		switch {
+		case entry.URL == "" && !entry.Code && entry.Items == nil:
+			value = proseBlocks("", entry.Value, raw)
+		case entry.URL != "" || entry.Value != "":
+			value = []ast.Block{&ast.Paragraph{Inlines: entry.inlines()}}
+		}
+
+		label := &ast.Strong{Inlines: []ast.Inline{raw(entry.Label)}}
+		if len(value) == 0 {
+			blocks = append(blocks, &ast.Paragraph{Inlines: []ast.Inline{label}})
+			continue
+		}
+
+		first := value[0].(*ast.Paragraph)
+		first.Inlines = append([]ast.Inline{label, &ast.LineBreak{}}, first.Inlines...)
+		blocks = append(blocks, value...)
+	}
+	return blocks
+}
+
+// inlines returns the value with each item wrapped in inline code when Code
+// is set, and the whole value in a link when the entry has a URL.
+func (entry labelledValue) inlines() []ast.Inline {
+	items := entry.Items
+	if items == nil {
+		items = []string{entry.Value}
+	}
+
+	// Inside a link the value is link text, which Markdown escapes
+	// differently from the JSON values written elsewhere.
+	part := raw
+	if entry.URL != "" {
+		part = text
+	}
+
+	inlines := make([]ast.Inline, 0, len(items)*2)
+	for i, item := range items {
+		if i > 0 {
+			inlines = append(inlines, part(entry.Separator))
+		}
+		if entry.Code {
+			inlines = append(inlines, &ast.Code{Value: item})
+			continue
+		}
+		inlines = append(inlines, part(item))
+	}
+
+	if entry.URL == "" {
+		return inlines
+	}
+	return []ast.Inline{&ast.Link{URL: entry.URL, Inlines: inlines}}
+}
+
+func cell(inlines ...ast.Inline) *ast.Cell {
+	return &ast.Cell{Inlines: inlines}
+}
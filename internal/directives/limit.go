@@ -0,0 +1,70 @@
+package directives
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+)
+
+const defaultMoreText = "…and {count} more"
+
+// validateWindow checks the limit and offset options shared by every
+// directive that renders array items.
+func validateWindow(directiveIndex int, directive plan.Directive) error {
+	if directive.Limit < 0 || directive.Offset < 0 {
+		return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "limit and offset must not be negative")
+	}
+	if directive.MoreText != "" && directive.Limit == 0 {
+		return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "more_text requires limit")
+	}
+	return nil
+}
+
+// window returns the range of the count items to render, skipping offset items
+// and rendering at most limit. A limit of zero renders every remaining item.
+func window(directive plan.Directive, count int) (int, int) {
+	start := min(directive.Offset, count)
+	end := count
+	if directive.Limit > 0 {
+		end = min(start+directive.Limit, count)
+	}
+	return start, end
+}
+
+// formatMore returns the summary line for the remaining items after the
+// window, or an empty string when none remain or no limit was requested. Items
+// skipped by offset come before the window and are not counted.
+func formatMore(directive plan.Directive, remaining int) string {
+	if remaining == 0 || directive.Limit == 0 {
+		return ""
+	}
+
+	more := directive.MoreText
+	if more == "" {
+		more = defaultMoreText
+	}
+	return strings.ReplaceAll(more, "{count}", strconv.Itoa(remaining))
+}
+
+// elideItems returns the leaf paths of the array items outside [start, end).
+// Each of those items must first pass check, the directive's own checks on an
+// item, so that whether a plan is valid does not depend on the window. A nil
+// check accepts every item.
+func elideItems(items []*jsondoc.Node, arrayTokens []string, start int, end int, check func(item *jsondoc.Node, tokens []string) error) ([]string, error) {
+	elided := make([]string, 0)
+	for index, item := range items {
+		if index >= start && index < end {
+			continue
+		}
+		tokens := append(append([]string{}, arrayTokens...), strconv.Itoa(index))
+		if check != nil {
+			if err := check(item, tokens); err != nil {
+				return nil, err
+			}
+		}
+		elided = append(elided, item.LeafPaths(tokens)...)
+	}
+	return elided, nil
+}
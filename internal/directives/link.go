@@ -0,0 +1,119 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type linkHandler struct{}
+
+func (linkHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+
+	target, label, consumed, err := resolveLinkParts(root, directiveIndex, directive)
+	if err != nil {
+		return nil, err
+	}
+
+	if label == "" {
+		label = target
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.Paragraph{Inlines: []ast.Inline{&ast.Link{URL: target, Inlines: []ast.Inline{text(label)}}}}},
+		Consumed: consumed,
+	}, nil
+}
+
+// resolveLinkParts resolves the URL at the directive path and the optional
+// link text given literally by text or resolved from text_path. It returns the
+// escaped URL, the unescaped text, and the consumed pointers.
+func resolveLinkParts(root *jsondoc.Node, directiveIndex int, directive plan.Directive) (string, string, []string, error) {
+	if directive.Text != "" && directive.TextPath != "" {
+		return "", "", nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "only one of text or text_path may be provided")
+	}
+
+	node, absolutePath, err := resolvePath(root, directiveIndex, directive.Path)
+	if err != nil {
+		return "", "", nil, err
+	}
+	target, err := formatLinkURL(directiveIndex, directive.Path, node)
+	if err != nil {
+		return "", "", nil, err
+	}
+	consumed := []string{absolutePath}
+
+	text := directive.Text
+	if directive.TextPath != "" {
+		textNode, textPath, err := resolvePath(root, directiveIndex, directive.TextPath)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if !textNode.IsScalar() {
+			return "", "", nil, nonScalarFieldError(directiveIndex, directive.TextPath)
+		}
+
+		text, err = textNode.FormatScalar()
+		if err != nil {
+			return "", "", nil, err
+		}
+		consumed = append(consumed, textPath)
+	}
+
+	return target, text, consumed, nil
+}
+
+// formatLinkURL validates that node holds a URL with one of the link schemes,
+// or a relative URL without a scheme, and percent-encodes the characters that
+// would otherwise end a Markdown link destination early.
+func formatLinkURL(directiveIndex int, path string, node *jsondoc.Node) (string, error) {
+	if node.Kind != jsondoc.String {
+		return "", invalidURLError(directiveIndex, path, "value must be a string")
+	}
+
+	raw := strings.TrimSpace(node.String)
+	if raw == "" {
+		return "", invalidURLError(directiveIndex, path, "value must not be empty")
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", invalidURLError(directiveIndex, path, "value could not be parsed")
+	}
+	if parsed.Scheme != "" && !markup.IsLinkScheme(parsed.Scheme) {
+		return "", invalidURLError(directiveIndex, path, fmt.Sprintf("scheme %q is not allowed", parsed.Scheme))
+	}
+
+	replacer := strings.NewReplacer(
+		" ", "%20",
+		"(", "%28",
+		")", "%29",
+		"<", "%3C",
+		">", "%3E",
+		"\t", "%09",
+		"\n", "%0A",
+		"\r", "%0D",
+	)
+	return replacer.Replace(raw), nil
+}
+
+func invalidURLError(directiveIndex int, path string, problem string) error {
+	return diagnostics.New(
+		"invalid_url",
+		directiveIndex,
+		path,
+		"URL at path %q is invalid: %s",
+		displayPath(path),
+		problem,
+	)
+}
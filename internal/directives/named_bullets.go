@@ -1,6 +1,7 @@
 package directives
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
@@ -9,11 +10,16 @@ import (
 
 type namedBulletsHandler struct{}
 
-func (namedBulletsHandler) Execute(root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+func (namedBulletsHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
 	if len(directive.Fields) == 0 {
 		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields must not be empty")
 	}
 
+	format, ok := labelledLayouts[directive.Layout]
+	if !ok {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("layout %q is not supported", directive.Layout))
+	}
+
 	target, targetPath, err := requirePath(root, directiveIndex, directive.Path, jsondoc.Object, directive.Op)
 	if err != nil {
 		return nil, err
@@ -23,33 +29,70 @@ func (namedBulletsHandler) Execute(root *jsondoc.Node, directiveIndex int, direc
 		return nil, err
 	}
 
-	lines := make([]string, 0, len(directive.Fields))
+	entries := make([]labelledValue, 0, len(directive.Fields))
 	consumed := make([]string, 0, len(directive.Fields))
 
 	for _, field := range directive.Fields {
 		if field.Path == "" || field.Path == "." {
 			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "field paths must not be empty")
 		}
+		if field.Separator != "" && !field.Inline {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "separator requires inline")
+		}
+		if field.Placeholder != "" && !field.Inline {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "placeholder requires inline")
+		}
 
 		node, absolutePath, err := jsondoc.Resolve(root, target, targetTokens, field.Path)
 		if err != nil {
 			return nil, missingFieldError(directiveIndex, field.Path)
 		}
-		if !node.IsScalar() {
+
+		entry := labelledValue{Label: field.Label, Code: field.Code}
+		switch {
+		case node.IsScalar():
+			entry.Value, err = node.FormatScalar()
+			if err != nil {
+				return nil, err
+			}
+			consumed = append(consumed, absolutePath)
+		case field.Inline && node.Kind == jsondoc.Array:
+			itemPaths, err := joinScalarArray(directiveIndex, field, node, absolutePath, &entry)
+			if err != nil {
+				return nil, err
+			}
+			consumed = append(consumed, itemPaths...)
+		default:
 			return nil, nonScalarFieldError(directiveIndex, field.Path)
 		}
 
-		value, err := node.FormatScalar()
-		if err != nil {
-			return nil, err
+		// An empty inline array has nothing to write, so the field is
+		// omitted unless it sets a placeholder.
+		if entry.Items != nil && len(entry.Items) == 0 {
+			if field.Placeholder == "" {
+				continue
+			}
+			entry.Items, entry.Value = nil, field.Placeholder
+		}
+
+		if field.Link != "" {
+			linkNode, linkPath, err := jsondoc.Resolve(root, target, targetTokens, field.Link)
+			if err != nil {
+				return nil, missingFieldError(directiveIndex, field.Link)
+			}
+			entry.URL, err = formatLinkURL(directiveIndex, field.Link, linkNode)
+			if err != nil {
+				return nil, err
+			}
+
+			consumed = append(consumed, linkPath)
 		}
 
-		lines = append(lines, formatBullet(fmt.Sprintf("**%s:** %s", field.Label, value)))
-		consumed = append(consumed, absolutePath)
+		entries = append(entries, entry)
 	}
 
 	return &Result{
-		Lines:    lines,
+		Blocks:   format(entries),
 		Consumed: consumed,
 	}, nil
 }
@@ -0,0 +1,197 @@
+package directives
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// truncated follows the label of a container whose members are nested deeper
+// than the depth limit.
+const truncated = "…"
+
+type nestedBulletsHandler struct{}
+
+func (nestedBulletsHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+	if directive.Depth < 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "depth must not be negative")
+	}
+	if err := validateGrouping(directiveIndex, directive); err != nil {
+		return nil, err
+	}
+	if err := validateWindow(directiveIndex, directive); err != nil {
+		return nil, err
+	}
+
+	target, absolutePath, err := resolvePath(root, directiveIndex, directive.Path)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsScalar() {
+		return nil, diagnostics.New(
+			"type_mismatch",
+			directiveIndex,
+			directive.Path,
+			"directive %q requires path %q to resolve to object or array",
+			directive.Op,
+			displayPath(directive.Path),
+		)
+	}
+
+	result := &Result{
+		Blocks:   make([]ast.Block, 0),
+		Consumed: make([]string, 0),
+	}
+
+	if directive.GroupBy == "" && directive.Limit == 0 && directive.Offset == 0 {
+		list := &ast.List{}
+		if err := writeNestedBullets(result, list, target, absolutePath, 0, directive.Depth); err != nil {
+			return nil, err
+		}
+		result.Blocks = append(result.Blocks, list)
+		return result, nil
+	}
+
+	if target.Kind != jsondoc.Array {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "group_by, limit, and offset require path to resolve to an array")
+	}
+	targetTokens, err := jsondoc.PointerTokens(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := window(directive, len(target.Array))
+	var checkItem func(*jsondoc.Node, []string) error
+	if directive.GroupBy != "" {
+		checkItem = func(item *jsondoc.Node, tokens []string) error {
+			_, _, err := groupKey(root, directiveIndex, directive, item, tokens)
+			return err
+		}
+	}
+	result.Elided, err = elideItems(target.Array, targetTokens, start, end, checkItem)
+	if err != nil {
+		return nil, err
+	}
+	more := formatMore(directive, len(target.Array)-end)
+
+	if directive.GroupBy == "" {
+		list := &ast.List{}
+		for index := start; index < end; index++ {
+			if err := writeNestedItem(result, list, index, target.Array[index], absolutePath, 0, directive.Depth); err != nil {
+				return nil, err
+			}
+		}
+		if more != "" {
+			list.Items = append(list.Items, &ast.ListItem{Inlines: []ast.Inline{text(more)}})
+		}
+		result.Blocks = append(result.Blocks, list)
+		return result, nil
+	}
+
+	tokens := make([][]string, 0, end-start)
+	for index := start; index < end; index++ {
+		tokens = append(tokens, append(append([]string{}, targetTokens...), strconv.Itoa(index)))
+	}
+
+	groups, consumed, err := groupElements(root, directiveIndex, directive, target.Array[start:end], tokens)
+	if err != nil {
+		return nil, err
+	}
+	result.Consumed = append(result.Consumed, consumed...)
+
+	for _, group := range groups {
+		list := &ast.List{}
+		for _, member := range group.Members {
+			index := start + member
+			if err := writeNestedItem(result, list, index, target.Array[index], absolutePath, 0, directive.Depth); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Blocks = append(result.Blocks, groupHeading(directive, group.Key), list)
+	}
+	if more != "" {
+		result.Blocks = append(result.Blocks, &ast.Paragraph{Inlines: []ast.Inline{text(more)}})
+	}
+
+	return result, nil
+}
+
+// writeNestedBullets appends one bullet per member of node to list, nesting
+// containers beneath a labelled bullet until maxDepth levels have been written.
+// Deeper containers are truncated and their leaves left unconsumed. A maxDepth
+// of zero walks the whole subtree.
+func writeNestedBullets(result *Result, list *ast.List, node *jsondoc.Node, pointer string, level int, maxDepth int) error {
+	switch node.Kind {
+	case jsondoc.Object:
+		for _, field := range node.Object {
+			if err := writeNestedMember(result, list, field.Name, field.Value, pointer+jsondoc.EncodePointer([]string{field.Name}), level, maxDepth); err != nil {
+				return err
+			}
+		}
+	case jsondoc.Array:
+		for index, item := range node.Array {
+			if err := writeNestedItem(result, list, index, item, pointer, level, maxDepth); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeNestedItem writes one array item. Scalar items are unlabelled and
+// containers are labelled with their index.
+func writeNestedItem(result *Result, list *ast.List, index int, item *jsondoc.Node, arrayPointer string, level int, maxDepth int) error {
+	label := ""
+	if !item.IsScalar() {
+		label = strconv.Itoa(index)
+	}
+	return writeNestedMember(result, list, label, item, arrayPointer+"/"+strconv.Itoa(index), level, maxDepth)
+}
+
+func writeNestedMember(result *Result, list *ast.List, label string, child *jsondoc.Node, childPointer string, level int, maxDepth int) error {
+	if child.IsScalar() {
+		value, err := child.FormatScalar()
+		if err != nil {
+			return err
+		}
+
+		item := &ast.ListItem{Inlines: []ast.Inline{raw(value)}}
+		if label != "" {
+			item.Inlines = []ast.Inline{lead(label), raw(" "), raw(value)}
+		}
+		list.Items = append(list.Items, item)
+		result.Consumed = append(result.Consumed, childPointer)
+		return nil
+	}
+
+	item := &ast.ListItem{Inlines: []ast.Inline{lead(label)}}
+	list.Items = append(list.Items, item)
+
+	if maxDepth > 0 && level+1 >= maxDepth {
+		// The members would be nested deeper than maxDepth, so the label is
+		// followed by a truncation marker. Their leaves are not visited and
+		// are left for coverage to report.
+		if len(child.Object) > 0 || len(child.Array) > 0 {
+			item.Inlines = append(item.Inlines, raw(" "), text(truncated))
+		}
+		return nil
+	}
+
+	children := &ast.List{}
+	if err := writeNestedBullets(result, children, child, childPointer, level+1, maxDepth); err != nil {
+		return err
+	}
+	if len(children.Items) > 0 {
+		item.Blocks = []ast.Block{children}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package directives
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+)
+
+// builtinOptions lists the options each built-in op accepts besides op, path,
+// and fields, which every handler checks itself.
+var builtinOptions = map[string][]string{
+	"alert":          {"kind", "kind_path", "kinds", "label"},
+	"blockquote":     {"label"},
+	"bullet_list":    {"limit", "offset", "more_text"},
+	"code_block":     {"language"},
+	"details":        {"summary", "summary_path", "directives"},
+	"front_matter":   {},
+	"image":          {"text", "text_path"},
+	"json_block":     {"language"},
+	"link":           {"text", "text_path"},
+	"named_bullets":  {"layout"},
+	"nested_bullets": {"depth", "group_by", "group_order", "heading_level", "limit", "offset", "more_text"},
+	"paragraph":      {"label"},
+	"rule":           {},
+	"table":          {"label", "layout", "header_path", "sort_by", "sort_order", "group_by", "group_order", "heading_level", "limit", "offset", "more_text"},
+	"text":           {"text", "markdown"},
+	"toc":            {"min_level", "max_level"},
+}
+
+// builtinFieldOptions lists the field options each built-in op that takes
+// fields accepts besides path. The other ops reject fields altogether.
+var builtinFieldOptions = map[string][]string{
+	"front_matter":  {"label", "value"},
+	"named_bullets": {"label", "link", "code", "inline", "separator", "placeholder"},
+	"table":         {"label", "link", "code", "align", "aggregate", "missing", "placeholder", "non_scalar", "separator"},
+}
+
+// checkOptions rejects a built-in directive that sets an option, or a field
+// option, its op does not read, which would otherwise be silently ignored.
+func checkOptions(directiveIndex int, directive plan.Directive) error {
+	allowed, ok := builtinOptions[directive.Op]
+	if !ok {
+		return nil
+	}
+
+	for _, option := range setOptions(directive) {
+		if !slices.Contains(allowed, option) {
+			return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("%s is not supported", option))
+		}
+	}
+
+	allowedFields, ok := builtinFieldOptions[directive.Op]
+	if !ok {
+		return nil
+	}
+	for _, field := range directive.Fields {
+		for _, option := range setFieldOptions(field) {
+			if !slices.Contains(allowedFields, option) {
+				return unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("field option %s is not supported", option))
+			}
+		}
+	}
+	return nil
+}
+
+// setOptions returns the plan names of the options that directive sets, in
+// the order Directive declares them. An option set to its zero value cannot be
+// told apart from one that is omitted, and has no effect either way.
+func setOptions(directive plan.Directive) []string {
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{"depth", directive.Depth != 0},
+		{"label", directive.Label != ""},
+		{"language", directive.Language != ""},
+		{"kind", directive.Kind != ""},
+		{"kind_path", directive.KindPath != ""},
+		{"kinds", len(directive.Kinds) > 0},
+		{"summary", directive.Summary != ""},
+		{"summary_path", directive.SummaryPath != ""},
+		{"directives", len(directive.Directives) > 0},
+		{"text", directive.Text != ""},
+		{"text_path", directive.TextPath != ""},
+		{"markdown", directive.Markdown},
+		{"min_level", directive.MinLevel != 0},
+		{"max_level", directive.MaxLevel != 0},
+		{"layout", directive.Layout != ""},
+		{"sort_by", directive.SortBy != ""},
+		{"sort_order", directive.SortOrder != ""},
+		{"header_path", directive.HeaderPath != ""},
+		{"group_by", directive.GroupBy != ""},
+		{"group_order", directive.GroupOrder != ""},
+		{"heading_level", directive.HeadingLevel != 0},
+		{"limit", directive.Limit != 0},
+		{"offset", directive.Offset != 0},
+		{"more_text", directive.MoreText != ""},
+	}
+
+	set := make([]string, 0)
+	for _, option := range options {
+		if option.set {
+			set = append(set, option.name)
+		}
+	}
+	return set
+}
+
+// setFieldOptions returns the plan names of the options that field sets
+// besides path, in the order Field declares them.
+func setFieldOptions(field plan.Field) []string {
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{"label", field.Label != ""},
+		{"link", field.Link != ""},
+		{"value", len(field.Value) > 0},
+		{"align", field.Align != ""},
+		{"aggregate", field.Aggregate != ""},
+		{"missing", field.Missing != ""},
+		{"placeholder", field.Placeholder != ""},
+		{"non_scalar", field.NonScalar != ""},
+		{"inline", field.Inline},
+		{"separator", field.Separator != ""},
+		{"code", field.Code},
+	}
+
+	set := make([]string, 0)
+	for _, option := range options {
+		if option.set {
+			set = append(set, option.name)
+		}
+	}
+	return set
+}
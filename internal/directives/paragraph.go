@@ -0,0 +1,65 @@
+package directives
+
+import (
+	"context"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type paragraphHandler struct{}
+
+func (paragraphHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+
+	target, absolutePath, err := requirePath(root, directiveIndex, directive.Path, jsondoc.String, directive.Op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Blocks:   proseBlocks(directive.Label, target.String, raw),
+		Consumed: []string{absolutePath},
+	}, nil
+}
+
+// proseBlocks converts text into paragraphs. Blank lines in the source
+// separate paragraphs and single line breaks become hard line breaks so they
+// survive rendering. inline converts each line, and a non-empty label is
+// written as a bold lead-in on the first line.
+func proseBlocks(label string, value string, inline func(string) ast.Inline) []ast.Block {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+
+	blocks := make([]ast.Block, 0)
+	var paragraph *ast.Paragraph
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			paragraph = nil
+			continue
+		}
+
+		if paragraph == nil {
+			paragraph = &ast.Paragraph{}
+			blocks = append(blocks, paragraph)
+		} else {
+			paragraph.Inlines = append(paragraph.Inlines, &ast.LineBreak{})
+		}
+		paragraph.Inlines = append(paragraph.Inlines, inline(line))
+	}
+
+	if label != "" {
+		if len(blocks) == 0 {
+			return []ast.Block{&ast.Paragraph{Inlines: []ast.Inline{lead(label)}}}
+		}
+		first := blocks[0].(*ast.Paragraph)
+		first.Inlines = append([]ast.Inline{lead(label), raw(" ")}, first.Inlines...)
+	}
+
+	return blocks
+}
@@ -0,0 +1,25 @@
+package directives
+
+import (
+	"context"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type ruleHandler struct{}
+
+func (ruleHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if directive.Path != "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "path is not supported")
+	}
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{&ast.ThematicBreak{}},
+		Consumed: []string{},
+	}, nil
+}
@@ -0,0 +1,570 @@
+package directives
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+var (
+	tableAlignments = []string{"", "left", "center", "right"}
+	tableAggregates = []string{"", "sum", "count", "min", "max"}
+	tableSortOrders = []string{"", "asc", "desc"}
+	tableLayouts    = []string{"", "rows", "transposed"}
+
+	tableMissingModes   = []string{"", "error", "blank", "placeholder"}
+	tableNonScalarModes = []string{"", "error", "json", "join"}
+)
+
+type tableHandler struct{}
+
+// tableRow holds the resolved cells of one array element in field order.
+type tableRow struct {
+	Element  *jsondoc.Node
+	Tokens   []string
+	Nodes    []*jsondoc.Node
+	Cells    []labelledValue
+	Header   string
+	Consumed []string
+}
+
+func (tableHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if len(directive.Fields) == 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields must not be empty")
+	}
+	for _, field := range directive.Fields {
+		if field.Path == "" || field.Path == "." {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "field paths must not be empty")
+		}
+		if !slices.Contains(tableAlignments, field.Align) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("align %q is not one of left, center, right", field.Align))
+		}
+		if !slices.Contains(tableAggregates, field.Aggregate) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("aggregate %q is not one of sum, count, min, max", field.Aggregate))
+		}
+		if !slices.Contains(tableMissingModes, field.Missing) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("missing %q is not one of error, blank, placeholder", field.Missing))
+		}
+		if (field.Missing == "placeholder") != (field.Placeholder != "") {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "placeholder must be set exactly when missing is placeholder")
+		}
+		if !slices.Contains(tableNonScalarModes, field.NonScalar) {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("non_scalar %q is not one of error, json, join", field.NonScalar))
+		}
+		if field.Separator != "" && field.NonScalar != "join" {
+			return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "separator requires non_scalar join")
+		}
+	}
+	if !slices.Contains(tableSortOrders, directive.SortOrder) {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("sort_order %q is not one of asc, desc", directive.SortOrder))
+	}
+	if directive.SortOrder != "" && directive.SortBy == "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "sort_order requires sort_by")
+	}
+	if err := validateGrouping(directiveIndex, directive); err != nil {
+		return nil, err
+	}
+	if err := validateWindow(directiveIndex, directive); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(tableLayouts, directive.Layout) {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, fmt.Sprintf("layout %q is not one of rows, transposed", directive.Layout))
+	}
+	transposed := directive.Layout == "transposed"
+	if transposed && directive.HeaderPath == "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "the transposed layout requires header_path")
+	}
+	if !transposed && directive.HeaderPath != "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "header_path requires the transposed layout")
+	}
+	if transposed && slices.ContainsFunc(directive.Fields, func(field plan.Field) bool { return field.Align != "" }) {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "align is not supported with the transposed layout")
+	}
+
+	target, targetPath, err := requirePath(root, directiveIndex, directive.Path, jsondoc.Array, directive.Op)
+	if err != nil {
+		return nil, err
+	}
+	targetTokens, err := jsondoc.PointerTokens(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]tableRow, 0, len(target.Array))
+
+	for index, element := range target.Array {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if element.Kind != jsondoc.Object {
+			return nil, diagnostics.New(
+				"non_object_item",
+				directiveIndex,
+				directive.Path,
+				"directive %q requires all array items at path %q to be objects",
+				directive.Op,
+				displayPath(directive.Path),
+			)
+		}
+
+		row := tableRow{
+			Element: element,
+			Tokens:  append(append([]string{}, targetTokens...), strconv.Itoa(index)),
+			Nodes:   make([]*jsondoc.Node, 0, len(directive.Fields)),
+			Cells:   make([]labelledValue, 0, len(directive.Fields)),
+		}
+
+		if transposed {
+			node, absolutePath, err := jsondoc.Resolve(root, element, row.Tokens, directive.HeaderPath)
+			if err != nil {
+				return nil, missingFieldError(directiveIndex, directive.HeaderPath)
+			}
+			if !node.IsScalar() {
+				return nil, nonScalarFieldError(directiveIndex, directive.HeaderPath)
+			}
+
+			row.Header, err = node.FormatScalar()
+			if err != nil {
+				return nil, err
+			}
+			row.Consumed = append(row.Consumed, absolutePath)
+		}
+
+		for _, field := range directive.Fields {
+			node, cell, cellConsumed, err := resolveTableCell(root, directiveIndex, element, row.Tokens, field)
+			if err != nil {
+				return nil, err
+			}
+
+			row.Nodes = append(row.Nodes, node)
+			row.Cells = append(row.Cells, cell)
+			row.Consumed = append(row.Consumed, cellConsumed...)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if directive.SortBy != "" {
+		if err := sortTableRows(root, directiveIndex, directive, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	// The window is applied after sorting so a limit keeps the first rows in
+	// sorted order.
+	start, end := window(directive, len(rows))
+	elided := make([]string, 0)
+	for i, row := range rows {
+		if i >= start && i < end {
+			continue
+		}
+		// Every row has been checked above except for its group, which is
+		// checked here so that validity does not depend on the window.
+		if directive.GroupBy != "" {
+			if _, _, err := groupKey(root, directiveIndex, directive, row.Element, row.Tokens); err != nil {
+				return nil, err
+			}
+		}
+		elided = append(elided, row.Element.LeafPaths(row.Tokens)...)
+	}
+	more := formatMore(directive, len(rows)-end)
+	rows = rows[start:end]
+
+	consumed := make([]string, 0, len(rows)*len(directive.Fields))
+	for _, row := range rows {
+		consumed = append(consumed, row.Consumed...)
+	}
+
+	blocks := make([]ast.Block, 0)
+	if directive.GroupBy == "" {
+		table, err := tableBlock(directiveIndex, directive, rows)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, table)
+	} else {
+		elements := make([]*jsondoc.Node, 0, len(rows))
+		tokens := make([][]string, 0, len(rows))
+		for _, row := range rows {
+			elements = append(elements, row.Element)
+			tokens = append(tokens, row.Tokens)
+		}
+
+		groups, groupConsumed, err := groupElements(root, directiveIndex, directive, elements, tokens)
+		if err != nil {
+			return nil, err
+		}
+		consumed = append(consumed, groupConsumed...)
+
+		for _, group := range groups {
+			members := make([]tableRow, 0, len(group.Members))
+			for _, member := range group.Members {
+				members = append(members, rows[member])
+			}
+
+			table, err := tableBlock(directiveIndex, directive, members)
+			if err != nil {
+				return nil, err
+			}
+
+			blocks = append(blocks, groupHeading(directive, group.Key), table)
+		}
+	}
+
+	if more != "" {
+		blocks = append(blocks, &ast.Paragraph{Inlines: []ast.Inline{text(more)}})
+	}
+
+	return &Result{
+		Blocks:   blocks,
+		Consumed: consumed,
+		Elided:   elided,
+	}, nil
+}
+
+// tableBlock renders rows in the directive's layout, including the totals
+// when any field has an aggregate.
+func tableBlock(directiveIndex int, directive plan.Directive, rows []tableRow) (*ast.Table, error) {
+	totals, err := tableTotals(directiveIndex, directive, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if directive.Layout == "transposed" {
+		return transposedTable(directive, rows, totals), nil
+	}
+	return rowTable(directive, rows, totals), nil
+}
+
+// rowTable renders one row per array item and one column per field, with the
+// totals, when present, as a final row.
+func rowTable(directive plan.Directive, rows []tableRow, totals []string) *ast.Table {
+	table := &ast.Table{
+		Header: make([]*ast.Cell, 0, len(directive.Fields)),
+		Align:  make([]ast.Alignment, 0, len(directive.Fields)),
+		Rows:   make([][]*ast.Cell, 0, len(rows)+1),
+	}
+	for _, field := range directive.Fields {
+		table.Header = append(table.Header, cell(raw(field.Label)))
+		table.Align = append(table.Align, ast.Alignment(field.Align))
+	}
+
+	for _, row := range rows {
+		cells := make([]*ast.Cell, 0, len(row.Cells))
+		for _, value := range row.Cells {
+			cells = append(cells, cell(value.inlines()...))
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	if totals != nil {
+		cells := make([]*ast.Cell, 0, len(totals))
+		for _, total := range totals {
+			cells = append(cells, cell(raw(total)))
+		}
+		if directive.Fields[0].Aggregate == "" {
+			cells[0] = cell(&ast.Strong{Inlines: []ast.Inline{raw("Total")}})
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	return table
+}
+
+// transposedTable renders one column per array item, headed by the value at
+// header_path, and one row per field, with the totals, when present, as a
+// final column.
+func transposedTable(directive plan.Directive, rows []tableRow, totals []string) *ast.Table {
+	table := &ast.Table{
+		Header: make([]*ast.Cell, 0, len(rows)+2),
+		Rows:   make([][]*ast.Cell, 0, len(directive.Fields)),
+	}
+	table.Header = append(table.Header, cell(raw(directive.Label)))
+	for _, row := range rows {
+		table.Header = append(table.Header, cell(raw(row.Header)))
+	}
+	if totals != nil {
+		table.Header = append(table.Header, cell(raw("Total")))
+	}
+
+	for column, field := range directive.Fields {
+		cells := make([]*ast.Cell, 0, len(rows)+2)
+		cells = append(cells, cell(&ast.Strong{Inlines: []ast.Inline{raw(field.Label)}}))
+		for _, row := range rows {
+			cells = append(cells, cell(row.Cells[column].inlines()...))
+		}
+		if totals != nil {
+			cells = append(cells, cell(raw(totals[column])))
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	return table
+}
+
+// resolveTableCell resolves one field of an array item. A missing value is
+// reported as an error or rendered as a blank or placeholder cell, and an
+// object or array value is reported as an error or rendered as inline JSON or
+// a joined list, as selected by the field. The returned node is nil when the
+// value is missing.
+func resolveTableCell(root *jsondoc.Node, directiveIndex int, element *jsondoc.Node, tokens []string, field plan.Field) (*jsondoc.Node, labelledValue, []string, error) {
+	cell := labelledValue{Label: field.Label, Code: field.Code}
+
+	node, absolutePath, err := jsondoc.Resolve(root, element, tokens, field.Path)
+	if err != nil {
+		switch field.Missing {
+		case "blank":
+			return nil, cell, nil, nil
+		case "placeholder":
+			cell.Value = field.Placeholder
+			return nil, cell, nil, nil
+		default:
+			return nil, cell, nil, missingFieldError(directiveIndex, field.Path)
+		}
+	}
+
+	consumed := make([]string, 0, 1)
+
+	switch {
+	case node.IsScalar():
+		cell.Value, err = node.FormatScalar()
+		if err != nil {
+			return nil, cell, nil, err
+		}
+		consumed = append(consumed, absolutePath)
+	case field.NonScalar == "json":
+		encoded, err := node.Marshal()
+		if err != nil {
+			return nil, cell, nil, err
+		}
+		cell.Value = string(encoded)
+		cell.Code = true
+
+		nodeTokens, err := jsondoc.PointerTokens(absolutePath)
+		if err != nil {
+			return nil, cell, nil, err
+		}
+		consumed = append(consumed, node.LeafPaths(nodeTokens)...)
+	case field.NonScalar == "join" && node.Kind == jsondoc.Array:
+		itemPaths, err := joinScalarArray(directiveIndex, field, node, absolutePath, &cell)
+		if err != nil {
+			return nil, cell, nil, err
+		}
+		consumed = append(consumed, itemPaths...)
+	default:
+		return nil, cell, nil, nonScalarFieldError(directiveIndex, field.Path)
+	}
+
+	if field.Link != "" {
+		linkNode, linkPath, err := jsondoc.Resolve(root, element, tokens, field.Link)
+		if err != nil {
+			return nil, cell, nil, missingFieldError(directiveIndex, field.Link)
+		}
+		cell.URL, err = formatLinkURL(directiveIndex, field.Link, linkNode)
+		if err != nil {
+			return nil, cell, nil, err
+		}
+
+		consumed = append(consumed, linkPath)
+	}
+
+	return node, cell, consumed, nil
+}
+
+// sortTableRows orders rows by the value at sort_by, resolved relative to each
+// element. The sort is stable so rows with equal keys keep their source order.
+func sortTableRows(root *jsondoc.Node, directiveIndex int, directive plan.Directive, rows []tableRow) error {
+	keys := make(map[*jsondoc.Node]*jsondoc.Node, len(rows))
+	for _, row := range rows {
+		node, _, err := jsondoc.Resolve(root, row.Element, row.Tokens, directive.SortBy)
+		if err != nil {
+			return missingFieldError(directiveIndex, directive.SortBy)
+		}
+		if !node.IsScalar() {
+			return nonScalarFieldError(directiveIndex, directive.SortBy)
+		}
+		keys[row.Element] = node
+	}
+
+	slices.SortStableFunc(rows, func(a, b tableRow) int {
+		order := compareScalars(keys[a.Element], keys[b.Element])
+		if directive.SortOrder == "desc" {
+			return -order
+		}
+		return order
+	})
+	return nil
+}
+
+// compareScalars orders numbers numerically and before every other value.
+// Other scalars are ordered by their formatted text.
+func compareScalars(a *jsondoc.Node, b *jsondoc.Node) int {
+	aNumber, aOK := parseNumber(a)
+	bNumber, bOK := parseNumber(b)
+
+	switch {
+	case aOK && bOK:
+		return aNumber.Cmp(bNumber)
+	case a.Kind == jsondoc.Number && b.Kind == jsondoc.Number:
+		// At least one exponent is out of range for an exact comparison;
+		// the nearest float64 values still order them.
+		return cmp.Compare(floatValue(a), floatValue(b))
+	case a.Kind == jsondoc.Number:
+		return -1
+	case b.Kind == jsondoc.Number:
+		return 1
+	}
+
+	aText, _ := a.FormatScalar()
+	bText, _ := b.FormatScalar()
+	return strings.Compare(aText, bText)
+}
+
+// maxExponent bounds the decimal exponent of numbers that are compared or
+// summed exactly. JSON allows any exponent, but the exact value of 1e-20000
+// has twenty thousand digits.
+const maxExponent = 1000
+
+// parseNumber returns the exact value of a number whose exponent is within
+// maxExponent.
+func parseNumber(node *jsondoc.Node) (*big.Rat, bool) {
+	if node.Kind != jsondoc.Number || !exponentInRange(node.Number) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(node.Number)
+}
+
+func exponentInRange(number string) bool {
+	index := strings.IndexAny(number, "eE")
+	if index < 0 {
+		return true
+	}
+	exponent, err := strconv.Atoi(number[index+1:])
+	return err == nil && exponent >= -maxExponent && exponent <= maxExponent
+}
+
+// floatValue returns the nearest float64 to a number, which is zero or an
+// infinity when its exponent is out of range.
+func floatValue(node *jsondoc.Node) float64 {
+	value, _ := strconv.ParseFloat(node.Number, 64)
+	return value
+}
+
+// tableTotals builds the summary row for columns with an aggregate, or returns
+// nil when no column has one. Null and missing values are skipped.
+func tableTotals(directiveIndex int, directive plan.Directive, rows []tableRow) ([]string, error) {
+	if !slices.ContainsFunc(directive.Fields, func(field plan.Field) bool { return field.Aggregate != "" }) {
+		return nil, nil
+	}
+
+	totals := make([]string, len(directive.Fields))
+	for column, field := range directive.Fields {
+		if field.Aggregate == "" {
+			continue
+		}
+
+		count := 0
+		var sum *big.Rat
+		var minimum, maximum *jsondoc.Node
+
+		for _, row := range rows {
+			node := row.Nodes[column]
+			if node == nil || node.Kind == jsondoc.Null {
+				continue
+			}
+			count++
+			if field.Aggregate == "count" {
+				continue
+			}
+
+			number, ok := parseNumber(node)
+			if !ok && node.Kind == jsondoc.Number {
+				return nil, diagnostics.New(
+					"number_out_of_range",
+					directiveIndex,
+					field.Path,
+					"aggregate %q requires exponents between -%d and %d at field path %q but found %s",
+					field.Aggregate,
+					maxExponent,
+					maxExponent,
+					field.Path,
+					node.Number,
+				)
+			}
+			if !ok {
+				value := row.Cells[column].Value
+				return nil, diagnostics.New(
+					"non_numeric_value",
+					directiveIndex,
+					field.Path,
+					"aggregate %q requires numeric values at field path %q but found %q",
+					field.Aggregate,
+					field.Path,
+					value,
+				)
+			}
+
+			if sum == nil {
+				sum = new(big.Rat)
+			}
+			sum.Add(sum, number)
+			if minimum == nil || compareScalars(node, minimum) < 0 {
+				minimum = node
+			}
+			if maximum == nil || compareScalars(node, maximum) > 0 {
+				maximum = node
+			}
+		}
+
+		switch field.Aggregate {
+		case "count":
+			totals[column] = strconv.Itoa(count)
+		case "sum":
+			if sum == nil {
+				sum = new(big.Rat)
+			}
+			totals[column] = formatDecimal(sum)
+		case "min":
+			if minimum != nil {
+				totals[column] = minimum.Number
+			}
+		case "max":
+			if maximum != nil {
+				totals[column] = maximum.Number
+			}
+		}
+	}
+
+	return totals, nil
+}
+
+// formatDecimal writes value with the fewest decimal places that represent it
+// exactly. Sums of JSON numbers always have a finite decimal expansion, so
+// the reduced denominator is 2^a * 5^b and needs max(a, b) places.
+func formatDecimal(value *big.Rat) string {
+	denominator := new(big.Int).Set(value.Denom())
+	twos := denominator.TrailingZeroBits()
+	denominator.Rsh(denominator, twos)
+
+	fives := 0
+	five := big.NewInt(5)
+	quotient, remainder := new(big.Int), new(big.Int)
+	for denominator.Cmp(big.NewInt(1)) > 0 {
+		quotient.QuoRem(denominator, five, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		denominator, quotient = quotient, denominator
+		fives++
+	}
+
+	return value.FloatString(max(int(twos), fives))
+}
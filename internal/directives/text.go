@@ -0,0 +1,124 @@
+package directives
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+var (
+	atxHeading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextRule   = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	codeFence    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	nonParagraph = regexp.MustCompile(`^(?: {4}|\t| {0,3}(?:[>#<|]|[*+-](?:[ \t]|$)|\d+[.)](?:[ \t]|$)))`)
+)
+
+type textHandler struct{}
+
+func (textHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if directive.Path != "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "path is not supported")
+	}
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+	if strings.TrimSpace(directive.Text) == "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "text must not be empty")
+	}
+
+	if directive.Markdown {
+		return &Result{
+			Blocks:   markdownBlocks(directive.Text),
+			Consumed: []string{},
+		}, nil
+	}
+
+	return &Result{
+		Blocks:   proseBlocks("", directive.Text, text),
+		Consumed: []string{},
+	}, nil
+}
+
+// markdownBlocks splits Markdown source into its top-level headings and raw
+// blocks holding the rest of the source verbatim. Headings become heading
+// blocks so that every output format can anchor them and toc can list them.
+// ATX headings and single-line setext headings outside fenced code blocks are
+// recognized.
+func markdownBlocks(source string) []ast.Block {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	blocks := make([]ast.Block, 0)
+	pending := make([]string, 0)
+	flush := func() {
+		start, end := 0, len(pending)
+		for start < end && strings.TrimSpace(pending[start]) == "" {
+			start++
+		}
+		for end > start && strings.TrimSpace(pending[end-1]) == "" {
+			end--
+		}
+		if start < end {
+			blocks = append(blocks, &ast.RawBlock{Lines: append([]string{}, pending[start:end]...)})
+		}
+		pending = pending[:0]
+	}
+	addHeading := func(level int, text string) {
+		flush()
+		blocks = append(blocks, &ast.Heading{Level: level, Inlines: []ast.Inline{raw(text)}})
+	}
+
+	fence := ""
+	for _, line := range lines {
+		if match := codeFence.FindStringSubmatch(line); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+			case match[1][0] == fence[0] && len(match[1]) >= len(fence) && strings.TrimSpace(line) == match[1]:
+				fence = ""
+			}
+			pending = append(pending, line)
+			continue
+		}
+		if fence != "" {
+			pending = append(pending, line)
+			continue
+		}
+
+		if match := atxHeading.FindStringSubmatch(line); match != nil {
+			addHeading(len(match[1]), strings.TrimSpace(match[2]))
+			continue
+		}
+		if match := setextRule.FindStringSubmatch(line); match != nil && setextContent(pending) {
+			text := strings.TrimSpace(pending[len(pending)-1])
+			pending = pending[:len(pending)-1]
+			level := 2
+			if match[1][0] == '=' {
+				level = 1
+			}
+			addHeading(level, text)
+			continue
+		}
+
+		pending = append(pending, line)
+	}
+	flush()
+
+	return blocks
+}
+
+// setextContent reports whether the last pending line is a paragraph of its
+// own that a setext underline turns into a heading.
+func setextContent(pending []string) bool {
+	if len(pending) == 0 {
+		return false
+	}
+	last := pending[len(pending)-1]
+	if strings.TrimSpace(last) == "" || nonParagraph.MatchString(last) {
+		return false
+	}
+	return len(pending) == 1 || strings.TrimSpace(pending[len(pending)-2]) == ""
+}
@@ -0,0 +1,123 @@
+package directives
+
+import (
+	"context"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markdown"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+type heading struct {
+	Level int
+	Text  string
+}
+
+type tocHandler struct{}
+
+func (tocHandler) needsDocument() {}
+
+func (tocHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*Result, error) {
+	if directive.Path != "" {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "path is not supported")
+	}
+	if len(directive.Fields) > 0 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "fields are not supported")
+	}
+
+	minLevel, maxLevel := directive.MinLevel, directive.MaxLevel
+	if minLevel == 0 {
+		minLevel = 1
+	}
+	if maxLevel == 0 {
+		maxLevel = 6
+	}
+	if minLevel < 1 || minLevel > 6 || maxLevel < 1 || maxLevel > 6 {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "min_level and max_level must be between 1 and 6")
+	}
+	if minLevel > maxLevel {
+		return nil, unexpectedPlanShape(directiveIndex, directive.Path, directive.Op, "min_level must not be greater than max_level")
+	}
+
+	return &Result{
+		Blocks:   []ast.Block{},
+		Consumed: []string{},
+		Finalize: func(preceding []ast.Block, following []ast.Block) []ast.Block {
+			return tocBlocks(collectHeadings(preceding), collectHeadings(following), minLevel, maxLevel)
+		},
+	}, nil
+}
+
+// collectHeadings returns the heading blocks among blocks in document order,
+// including headings nested in quotes, alerts, details, and list items. These
+// are the headings that every output format anchors.
+func collectHeadings(blocks []ast.Block) []heading {
+	headings := make([]heading, 0)
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *ast.Heading:
+			headings = append(headings, heading{Level: b.Level, Text: markdown.Inlines(b.Inlines)})
+		case *ast.BlockQuote:
+			headings = append(headings, collectHeadings(b.Blocks)...)
+		case *ast.Alert:
+			headings = append(headings, collectHeadings(b.Blocks)...)
+		case *ast.Details:
+			headings = append(headings, collectHeadings(b.Blocks)...)
+		case *ast.List:
+			for _, item := range b.Items {
+				headings = append(headings, collectHeadings(item.Blocks)...)
+			}
+		}
+	}
+	return headings
+}
+
+// tocBlocks renders the headings within the level range as a nested list of
+// links, using GitHub-compatible anchors. Anchors are assigned to every
+// heading, including those before the table of contents and those outside the
+// range, so that duplicate suffixes match the ones GitHub generates.
+func tocBlocks(earlier []heading, headings []heading, minLevel int, maxLevel int) []ast.Block {
+	list := &ast.List{}
+	anchors := markdown.Anchors{}
+	levels := make([]int, 0)
+	parents := make([]*ast.ListItem, 0)
+
+	for _, h := range earlier {
+		anchors.Add(markdown.LinkText(h.Text))
+	}
+
+	for _, h := range headings {
+		text := markdown.LinkText(h.Text)
+		anchor := anchors.Add(text)
+
+		if h.Level < minLevel || h.Level > maxLevel {
+			continue
+		}
+
+		for len(levels) > 0 && levels[len(levels)-1] >= h.Level {
+			levels = levels[:len(levels)-1]
+			parents = parents[:len(parents)-1]
+		}
+
+		// Heading text is already Markdown, so it is not escaped again.
+		item := &ast.ListItem{Inlines: []ast.Inline{&ast.Link{URL: "#" + anchor, Inlines: []ast.Inline{raw(text)}}}}
+		siblings := list
+		if len(parents) > 0 {
+			parent := parents[len(parents)-1]
+			if len(parent.Blocks) == 0 {
+				parent.Blocks = []ast.Block{&ast.List{}}
+			}
+			siblings = parent.Blocks[0].(*ast.List)
+		}
+		siblings.Items = append(siblings.Items, item)
+
+		levels = append(levels, h.Level)
+		parents = append(parents, item)
+	}
+
+	if len(list.Items) == 0 {
+		return nil
+	}
+	return []ast.Block{list}
+}
@@ -1,25 +1,61 @@
 package engine
 
 import (
+	"context"
+	"io"
+	"slices"
 	"strings"
 
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/directives"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
 	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
 )
 
 type Evaluation struct {
-	Lines []string
+	Document *ast.Document
+	// Lines is the document in the requested format.
+	Lines  []string
+	Elided []string
 }
 
-func Validate(root *jsondoc.Node, parsedPlan *plan.Plan) error {
-	_, err := evaluate(root, parsedPlan)
+// Serializer writes the top-level blocks of a document in an output format.
+// Block is called for each block in document order and End once after the
+// last block, so a Serializer may keep state between calls. The outputs of
+// consecutive calls are separated by a blank line. An error from End fails
+// the rendering.
+type Serializer interface {
+	Block(block ast.Block) []string
+	End() ([]string, error)
+}
+
+// Format creates the Serializer for one rendering.
+type Format func() Serializer
+
+// Limits bounds the work done for one plan. A zero field means no limit.
+type Limits struct {
+	// MaxDirectives limits the number of directives in the plan, including
+	// directives nested in other directives.
+	MaxDirectives int
+	// MaxOutputBytes limits the size of the rendered output.
+	MaxOutputBytes int
+}
+
+func Validate(ctx context.Context, root *jsondoc.Node, parsedPlan *plan.Plan, limits Limits, format Format) error {
+	_, err := evaluate(ctx, root, parsedPlan, limits, format, nil)
 	return err
 }
 
-func Render(root *jsondoc.Node, parsedPlan *plan.Plan) (string, error) {
-	evaluation, err := evaluate(root, parsedPlan)
+// Evaluate runs the plan against the JSON document and returns the rendered
+// document along with the paths that the plan deliberately elided.
+func Evaluate(ctx context.Context, root *jsondoc.Node, parsedPlan *plan.Plan, limits Limits, format Format) (*Evaluation, error) {
+	return evaluate(ctx, root, parsedPlan, limits, format, nil)
+}
+
+func Render(ctx context.Context, root *jsondoc.Node, parsedPlan *plan.Plan, limits Limits, format Format) (string, error) {
+	evaluation, err := evaluate(ctx, root, parsedPlan, limits, format, nil)
 	if err != nil {
 		return "", err
 	}
@@ -27,37 +63,191 @@ func Render(root *jsondoc.Node, parsedPlan *plan.Plan) (string, error) {
 	return strings.Join(evaluation.Lines, "\n"), nil
 }
 
-func evaluate(root *jsondoc.Node, parsedPlan *plan.Plan) (*Evaluation, error) {
-	if parsedPlan.Version != 1 {
+// Stream runs the plan and writes the same output as Render to out, writing
+// each directive's output as soon as it is rendered. When a directive needs
+// the rest of the document, such as a table of contents, the whole document
+// is written after the last directive instead.
+//
+// Coverage can only be verified once every directive has run, so out may
+// have received partial output when Stream fails. Callers must hold the
+// output back until Stream succeeds.
+func Stream(ctx context.Context, root *jsondoc.Node, parsedPlan *plan.Plan, limits Limits, format Format, out io.Writer) ([]string, error) {
+	evaluation, err := evaluate(ctx, root, parsedPlan, limits, format, out)
+	if err != nil {
+		return nil, err
+	}
+	return evaluation.Elided, nil
+}
+
+func evaluate(ctx context.Context, root *jsondoc.Node, parsedPlan *plan.Plan, limits Limits, format Format, out io.Writer) (*Evaluation, error) {
+	if parsedPlan.Version != plan.Version {
 		return nil, diagnostics.New("unsupported_version", -1, "", "plan version %d is not supported", parsedPlan.Version)
 	}
+	if limits.MaxDirectives > 0 && countDirectives(parsedPlan.Directives) > limits.MaxDirectives {
+		return nil, diagnostics.New("too_many_directives", -1, "", "plan has more than %d directives", limits.MaxDirectives)
+	}
+
+	streaming := out != nil && !slices.ContainsFunc(parsedPlan.Directives, func(directive plan.Directive) bool {
+		return directives.NeedsDocument(directive.Op)
+	})
+	writer := &blockWriter{out: out}
+	budget := &outputBudget{max: limits.MaxOutputBytes}
+	serializer := format()
 
 	consumed := make(map[string]struct{})
-	lines := make([]string, 0)
+	elided := make(map[string]struct{})
+	results := make([]*directives.Result, 0, len(parsedPlan.Directives))
 
 	for index, directive := range parsedPlan.Directives {
-		result, err := directives.Execute(root, index, directive)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := directives.Execute(ctx, root, index, directive)
 		if err != nil {
 			return nil, err
 		}
+		lines := serializeBlocks(serializer, result.Blocks)
+		if err := budget.add(lines); err != nil {
+			return nil, err
+		}
 
-		lines = append(lines, result.Lines...)
 		for _, path := range result.Consumed {
 			consumed[path] = struct{}{}
 		}
+		for _, path := range result.Elided {
+			elided[path] = struct{}{}
+		}
+
+		if streaming {
+			if err := writer.writeBlock(lines); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		results = append(results, result)
 	}
 
+	// A path rendered by any directive is covered even if another directive
+	// elided it, so only paths that were never rendered are reported as elided.
+	elidedPaths := make([]string, 0)
 	for _, path := range root.LeafPaths(nil) {
-		if _, ok := consumed[path]; !ok {
-			return nil, diagnostics.New(
-				"missing_coverage",
-				-1,
-				path,
-				"plan does not cover JSON path %q",
-				path,
-			)
+		if _, ok := consumed[path]; ok {
+			continue
+		}
+		if _, ok := elided[path]; ok {
+			elidedPaths = append(elidedPaths, path)
+			continue
+		}
+
+		return nil, diagnostics.New(
+			"missing_coverage",
+			-1,
+			path,
+			"plan does not cover JSON path %q",
+			path,
+		)
+	}
+
+	document := &ast.Document{Blocks: directives.Assemble(results)}
+	if streaming {
+		end, err := serializer.End()
+		if err != nil {
+			return nil, err
+		}
+		if err := budget.add(end); err != nil {
+			return nil, err
+		}
+		if err := writer.writeBlock(end); err != nil {
+			return nil, err
 		}
+		return &Evaluation{Document: document, Elided: elidedPaths}, nil
 	}
 
-	return &Evaluation{Lines: lines}, nil
+	// Finalize steps add output that the budget has not seen yet, so the
+	// whole document is serialized and measured again.
+	final := format()
+	lines := serializeBlocks(final, document.Blocks)
+	end, err := final.End()
+	if err != nil {
+		return nil, err
+	}
+	lines = markup.AppendBlock(lines, end)
+	if err := (&outputBudget{max: limits.MaxOutputBytes}).add(lines); err != nil {
+		return nil, err
+	}
+	if out != nil {
+		if err := writer.writeBlock(lines); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Evaluation{
+		Document: document,
+		Lines:    lines,
+		Elided:   elidedPaths,
+	}, nil
+}
+
+// serializeBlocks serializes consecutive blocks, separated by a blank line.
+func serializeBlocks(serializer Serializer, blocks []ast.Block) []string {
+	return markup.BlockLines(blocks, serializer.Block)
+}
+
+func countDirectives(list []plan.Directive) int {
+	count := len(list)
+	for _, directive := range list {
+		count += countDirectives(directive.Directives)
+	}
+	return count
+}
+
+// outputBudget tracks the size of the output produced by a sequence of
+// blocks, counting the blank lines that separate them.
+type outputBudget struct {
+	max     int
+	used    int
+	started bool
+}
+
+func (b *outputBudget) add(lines []string) error {
+	if b.max <= 0 || len(lines) == 0 {
+		return nil
+	}
+
+	if b.started {
+		b.used += 2
+	}
+	b.started = true
+	b.used += len(lines) - 1
+	for _, line := range lines {
+		b.used += len(line)
+	}
+
+	if b.used > b.max {
+		return diagnostics.New("output_too_large", -1, "", "rendered output exceeds %d bytes", b.max)
+	}
+	return nil
+}
+
+// blockWriter writes rendered blocks separated by a blank line, matching the
+// output of Render.
+type blockWriter struct {
+	out     io.Writer
+	written bool
+}
+
+func (w *blockWriter) writeBlock(lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	text := strings.Join(lines, "\n")
+	if w.written {
+		text = "\n\n" + text
+	}
+	w.written = true
+
+	_, err := io.WriteString(w.out, text)
+	return err
 }
@@ -0,0 +1,304 @@
+// Package html serializes an ast.Document as sanitized HTML. Every value is
+// escaped, and links are only written for URLs with a safe scheme.
+package html
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markdown"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+var escaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&#34;",
+	"'", "&#39;",
+)
+
+// Serializer writes blocks as HTML. Heading blocks, including the headings of
+// Markdown text, get ids that match the anchors of the Markdown output, which
+// are the anchors toc links to. When Standalone is set, the output is a
+// complete document with a minimal stylesheet, and front matter becomes the
+// document title and meta elements. Otherwise front matter is not written.
+type Serializer struct {
+	Standalone bool
+
+	anchors markdown.Anchors
+	started bool
+}
+
+// New returns a Serializer for one document.
+func New(standalone bool) *Serializer {
+	return &Serializer{Standalone: standalone, anchors: markdown.Anchors{}}
+}
+
+// Block returns the HTML lines of one top-level block. In a standalone
+// document the first block is preceded by the document head.
+func (s *Serializer) Block(block ast.Block) []string {
+	if !s.Standalone || s.started {
+		return s.block(block)
+	}
+
+	s.started = true
+	frontMatter, _ := block.(*ast.FrontMatter)
+	return append(head(frontMatter), s.block(block)...)
+}
+
+// End returns the lines that close a standalone document.
+func (s *Serializer) End() ([]string, error) {
+	if !s.Standalone {
+		return nil, nil
+	}
+
+	lines := make([]string, 0)
+	if !s.started {
+		s.started = true
+		lines = append(lines, head(nil)...)
+	}
+	return append(lines, "</body>", "</html>"), nil
+}
+
+func (s *Serializer) blocks(blocks []ast.Block) []string {
+	lines := make([]string, 0)
+	for _, block := range blocks {
+		lines = append(lines, s.block(block)...)
+	}
+	return lines
+}
+
+func (s *Serializer) block(block ast.Block) []string {
+	switch b := block.(type) {
+	case *ast.Heading:
+		anchor := s.anchors.Add(markdown.LinkText(markdown.Inlines(b.Inlines)))
+		return []string{fmt.Sprintf(`<h%d id="%s">%s</h%d>`, b.Level, escape(anchor), inlines(b.Inlines), b.Level)}
+	case *ast.Paragraph:
+		return []string{"<p>" + inlines(b.Inlines) + "</p>"}
+	case *ast.List:
+		return s.list(b)
+	case *ast.Table:
+		return table(b)
+	case *ast.CodeBlock:
+		return []string{codeBlock(b)}
+	case *ast.BlockQuote:
+		return wrap("<blockquote>", s.blocks(b.Blocks), "</blockquote>")
+	case *ast.Alert:
+		kind := strings.ToLower(b.Kind)
+		title := fmt.Sprintf(`<p class="alert-title">%s</p>`, escape(markup.AlertTitle(b.Kind)))
+		return wrap(fmt.Sprintf(`<div class="alert alert-%s">`, escape(kind)), append([]string{title}, s.blocks(b.Blocks)...), "</div>")
+	case *ast.Details:
+		summary := "<summary>" + escape(b.Summary) + "</summary>"
+		return wrap("<details>", append([]string{summary}, s.blocks(b.Blocks)...), "</details>")
+	case *ast.DefinitionList:
+		lines := []string{"<dl>"}
+		for _, item := range b.Items {
+			lines = append(lines, "<dt>"+escape(item.Term)+"</dt>", "<dd>"+inlines(item.Inlines)+"</dd>")
+		}
+		return append(lines, "</dl>")
+	case *ast.ThematicBreak:
+		return []string{"<hr>"}
+	case *ast.FrontMatter:
+		// A standalone document also has the fields in its head, but <meta>
+		// elements are not displayed.
+		lines := []string{"<dl>"}
+		for _, field := range b.Fields {
+			lines = append(lines, "<dt>"+escape(field.Key)+"</dt>", "<dd>"+escapeText(field.Value)+"</dd>")
+		}
+		return append(lines, "</dl>")
+	case *ast.RawBlock:
+		return rawBlock(b)
+	default:
+		panic(fmt.Sprintf("html: unsupported block %T", block))
+	}
+}
+
+func (s *Serializer) list(list *ast.List) []string {
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	lines := []string{"<ul>"}
+	for _, item := range list.Items {
+		content := inlines(item.Inlines)
+		if len(item.Blocks) == 0 {
+			lines = append(lines, "<li>"+content+"</li>")
+			continue
+		}
+		lines = append(lines, "<li>"+content)
+		lines = append(lines, s.blocks(item.Blocks)...)
+		lines = append(lines, "</li>")
+	}
+	return append(lines, "</ul>")
+}
+
+func table(table *ast.Table) []string {
+	lines := []string{"<table>", "<thead>", tableRow("th", table.Header, table.Align), "</thead>", "<tbody>"}
+	for _, row := range table.Rows {
+		lines = append(lines, tableRow("td", row, table.Align))
+	}
+	return append(lines, "</tbody>", "</table>")
+}
+
+func tableRow(tag string, cells []*ast.Cell, align []ast.Alignment) string {
+	var b strings.Builder
+	b.WriteString("<tr>")
+	for i, cell := range cells {
+		b.WriteString("<" + tag)
+		if i < len(align) && align[i] != ast.AlignDefault {
+			fmt.Fprintf(&b, ` style="text-align: %s"`, escape(string(align[i])))
+		}
+		b.WriteString(">" + inlines(cell.Inlines) + "</" + tag + ">")
+	}
+	b.WriteString("</tr>")
+	return b.String()
+}
+
+func codeBlock(block *ast.CodeBlock) string {
+	content := strings.ReplaceAll(block.Content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+
+	open := "<pre><code>"
+	if block.Language != "" {
+		open = fmt.Sprintf(`<pre><code class="language-%s">`, escape(block.Language))
+	}
+	return open + escape(content) + "</code></pre>"
+}
+
+// rawBlock writes Markdown source as text, one paragraph per run of
+// non-blank lines. The Markdown is not interpreted.
+func rawBlock(block *ast.RawBlock) []string {
+	lines := make([]string, 0)
+	paragraph := make([]string, 0)
+	flush := func() {
+		if len(paragraph) > 0 {
+			lines = append(lines, "<p>"+strings.Join(paragraph, "<br>\n")+"</p>")
+			paragraph = paragraph[:0]
+		}
+	}
+
+	for _, line := range block.Lines {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, escape(line))
+	}
+	flush()
+	return lines
+}
+
+func inlines(inlines []ast.Inline) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		b.WriteString(inlineHTML(inline))
+	}
+	return b.String()
+}
+
+func inlineHTML(inline ast.Inline) string {
+	switch n := inline.(type) {
+	case *ast.Text:
+		return escapeText(n.Value)
+	case *ast.Raw:
+		return escapeText(n.Value)
+	case *ast.Strong:
+		return "<strong>" + inlines(n.Inlines) + "</strong>"
+	case *ast.Code:
+		return "<code>" + escape(n.Value) + "</code>"
+	case *ast.Link:
+		target, ok := safeURL(n.URL)
+		if !ok {
+			return inlines(n.Inlines)
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, escape(target), inlines(n.Inlines))
+	case *ast.Image:
+		// Images are linked rather than embedded, so that the output loads
+		// no external resources.
+		target, ok := safeURL(n.URL)
+		if !ok {
+			return escapeText(n.Alt)
+		}
+		text := n.Alt
+		if text == "" {
+			text = n.URL
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, escape(target), escapeText(text))
+	case *ast.LineBreak:
+		return "<br>\n"
+	default:
+		panic(fmt.Sprintf("html: unsupported inline %T", inline))
+	}
+}
+
+// head returns the start of a standalone document up to the opening body tag.
+func head(frontMatter *ast.FrontMatter) []string {
+	lines := []string{
+		"<!DOCTYPE html>",
+		"<html>",
+		"<head>",
+		`<meta charset="utf-8">`,
+		`<meta name="viewport" content="width=device-width, initial-scale=1">`,
+	}
+	if frontMatter != nil {
+		for _, field := range frontMatter.Fields {
+			if field.Key == "title" {
+				lines = append(lines, "<title>"+escape(field.Value)+"</title>")
+			}
+		}
+		for _, field := range frontMatter.Fields {
+			if field.Key != "title" {
+				lines = append(lines, fmt.Sprintf(`<meta name="%s" content="%s">`, escape(field.Key), escape(field.Value)))
+			}
+		}
+	}
+	lines = append(lines, "<style>")
+	lines = append(lines, strings.Split(strings.TrimSpace(stylesheet), "\n")...)
+	return append(lines, "</style>", "</head>", "<body>")
+}
+
+const stylesheet = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #1f2328; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d1d9e0; padding: 0.25rem 0.75rem; }
+code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; }
+blockquote { margin-left: 0; padding: 0 1rem; color: #59636e; border-left: 0.25rem solid #d1d9e0; }
+.alert { padding: 0 1rem; border-left: 0.25rem solid #0969da; }
+.alert-tip { border-color: #1a7f37; }
+.alert-important { border-color: #8250df; }
+.alert-warning { border-color: #9a6700; }
+.alert-caution { border-color: #d1242f; }
+.alert-title { font-weight: 600; }
+dt { font-weight: 600; }
+dd { margin: 0 0 0.5rem 1rem; }
+`
+
+func wrap(open string, lines []string, close string) []string {
+	return append(append([]string{open}, lines...), close)
+}
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// escapeText escapes a value and keeps its line breaks.
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.ReplaceAll(escape(value), "\n", "<br>\n")
+}
+
+// safeURL returns the URL when it is relative or uses one of the link schemes.
+func safeURL(value string) (string, bool) {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	if parsed.Scheme != "" && !markup.IsLinkScheme(parsed.Scheme) {
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,216 @@
+// Package jira serializes an ast.Document as Jira and Confluence wiki markup.
+// Every character that wiki markup would interpret is escaped, and links are
+// only written for URLs with a safe scheme.
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// reserved lists the characters percent-encoded in URLs because they would
+// end a link or an image.
+const reserved = "|]! "
+
+// escaper escapes the characters that start wiki markup. A backslash is
+// written as an entity because two of them are a line break.
+var escaper = strings.NewReplacer(
+	`\`, "&#92;",
+	"*", `\*`,
+	"_", `\_`,
+	"-", `\-`,
+	"+", `\+`,
+	"^", `\^`,
+	"~", `\~`,
+	"#", `\#`,
+	"!", `\!`,
+	"|", `\|`,
+	"[", `\[`,
+	"]", `\]`,
+	"{", `\{`,
+	"}", `\}`,
+)
+
+// Serializer writes blocks as wiki markup. Front matter is written as bold
+// keys followed by their values.
+type Serializer struct{}
+
+func (Serializer) Block(block ast.Block) []string {
+	return Block(block)
+}
+
+func (Serializer) End() ([]string, error) {
+	return nil, nil
+}
+
+// Block returns the wiki markup lines of one block.
+func Block(block ast.Block) []string {
+	switch b := block.(type) {
+	case *ast.Heading:
+		return []string{"h" + strconv.Itoa(b.Level) + ". " + singleLine(b.Inlines)}
+	case *ast.Paragraph:
+		return strings.Split(inlines(b.Inlines), "\n")
+	case *ast.List:
+		return listLines(b, 1)
+	case *ast.Table:
+		lines := make([]string, 0, len(b.Rows)+1)
+		lines = append(lines, tableRow("||", b.Header))
+		for _, row := range b.Rows {
+			lines = append(lines, tableRow("|", row))
+		}
+		return lines
+	case *ast.CodeBlock:
+		return codeLines(b)
+	case *ast.BlockQuote:
+		return wrap("{quote}", markup.BlockLines(b.Blocks, Block), "{quote}")
+	case *ast.Alert:
+		return wrap("{panel:title="+markup.AlertTitle(b.Kind)+"}", markup.BlockLines(b.Blocks, Block), "{panel}")
+	case *ast.Details:
+		// Wiki markup has no collapsible content, so the summary becomes a
+		// bold paragraph above the content.
+		return append([]string{"*" + escape(b.Summary) + "*", ""}, markup.BlockLines(b.Blocks, Block)...)
+	case *ast.DefinitionList:
+		lines := make([]string, 0, len(b.Items))
+		for _, item := range b.Items {
+			lines = append(lines, "*"+escape(item.Term)+":* "+singleLine(item.Inlines))
+		}
+		return lines
+	case *ast.ThematicBreak:
+		return []string{"----"}
+	case *ast.FrontMatter:
+		// Wiki markup has no document metadata, so front matter is written
+		// like a definition list.
+		lines := make([]string, 0, len(b.Fields))
+		for _, field := range b.Fields {
+			value := strings.ReplaceAll(escape(field.Value), "\n", ` \\ `)
+			lines = append(lines, strings.TrimRight("*"+escape(field.Key)+":* "+value, " "))
+		}
+		return lines
+	case *ast.RawBlock:
+		lines := make([]string, 0, len(b.Lines))
+		for _, line := range b.Lines {
+			lines = append(lines, escape(line))
+		}
+		return lines
+	default:
+		panic(fmt.Sprintf("jira: unsupported block %T", block))
+	}
+}
+
+// listLines writes one bullet per item, with one asterisk per level of
+// nesting. Other content beneath an item follows it on lines of its own.
+func listLines(list *ast.List, level int) []string {
+	marker := strings.Repeat("*", level)
+
+	lines := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		lines = append(lines, marker+" "+singleLine(item.Inlines))
+		for _, block := range item.Blocks {
+			if nested, ok := block.(*ast.List); ok {
+				lines = append(lines, listLines(nested, level+1)...)
+				continue
+			}
+			lines = append(lines, Block(block)...)
+		}
+	}
+	return lines
+}
+
+// tableRow writes one row between separators. Wiki markup has no column
+// alignment, and a cell must not be empty.
+func tableRow(separator string, cells []*ast.Cell) string {
+	var b strings.Builder
+	b.WriteString(separator)
+	for _, cell := range cells {
+		value := singleLine(cell.Inlines)
+		if value == "" {
+			value = " "
+		}
+		b.WriteString(value)
+		b.WriteString(separator)
+	}
+	return b.String()
+}
+
+// codeLines writes a code macro, or a noformat macro when the content would
+// end a code macro early. When the content would end either macro, the
+// noformat macro is used with the closing brace of each "{noformat}" in the
+// content escaped.
+func codeLines(block *ast.CodeBlock) []string {
+	content := strings.ReplaceAll(block.Content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+
+	open, close := "{code}", "{code}"
+	if block.Language != "" {
+		open = "{code:" + block.Language + "}"
+	}
+	if strings.Contains(content, "{code") {
+		open, close = "{noformat}", "{noformat}"
+		content = strings.ReplaceAll(content, "{noformat}", `{noformat\}`)
+	}
+	return wrap(open, strings.Split(content, "\n"), close)
+}
+
+func wrap(open string, lines []string, close string) []string {
+	return append(append([]string{open}, lines...), close)
+}
+
+func inlines(inlines []ast.Inline) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		b.WriteString(inlineWiki(inline))
+	}
+	return b.String()
+}
+
+// singleLine returns inline content that must fit on one line, such as a
+// bullet or a table cell, with line breaks written as wiki line breaks.
+func singleLine(content []ast.Inline) string {
+	return strings.ReplaceAll(inlines(content), "\n", ` \\ `)
+}
+
+func inlineWiki(inline ast.Inline) string {
+	switch n := inline.(type) {
+	case *ast.Text:
+		return escape(n.Value)
+	case *ast.Raw:
+		return escape(n.Value)
+	case *ast.Strong:
+		text := inlines(n.Inlines)
+		if text == "" {
+			return ""
+		}
+		return "*" + text + "*"
+	case *ast.Code:
+		if n.Value == "" {
+			return ""
+		}
+		return "{{" + escape(n.Value) + "}}"
+	case *ast.Link:
+		// Wiki markup resolves targets without a scheme as page names, so
+		// only absolute URLs are written as links.
+		target, ok := markup.LinkURL(n.URL, reserved)
+		if !ok {
+			return inlines(n.Inlines)
+		}
+		return "[" + strings.ReplaceAll(inlines(n.Inlines), "\n", " ") + "|" + target + "]"
+	case *ast.Image:
+		target, ok := markup.ImageURL(n.URL, reserved)
+		if !ok {
+			return escape(n.Alt)
+		}
+		return "!" + target + "!"
+	case *ast.LineBreak:
+		return "\n"
+	default:
+		panic(fmt.Sprintf("jira: unsupported inline %T", inline))
+	}
+}
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
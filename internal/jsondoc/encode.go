@@ -0,0 +1,99 @@
+package jsondoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Marshal encodes the node as compact JSON on a single line. Like
+// MarshalIndent it preserves member order and number text.
+func (n *Node) Marshal() ([]byte, error) {
+	return n.MarshalIndent("")
+}
+
+// MarshalIndent encodes the node as indented JSON. Unlike encoding/json it
+// preserves the source order of object members and the original text of
+// numbers. An empty indent produces compact JSON.
+func (n *Node) MarshalIndent(indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := n.writeJSON(&buf, indent, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *Node) writeJSON(buf *bytes.Buffer, indent string, level int) error {
+	switch n.Kind {
+	case Object:
+		if len(n.Object) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{")
+		for i, field := range n.Object {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeNewline(buf, indent, level+1)
+			if err := writeJSONString(buf, field.Name); err != nil {
+				return err
+			}
+			buf.WriteString(":")
+			if indent != "" {
+				buf.WriteString(" ")
+			}
+			if err := field.Value.writeJSON(buf, indent, level+1); err != nil {
+				return err
+			}
+		}
+		writeNewline(buf, indent, level)
+		buf.WriteString("}")
+	case Array:
+		if len(n.Array) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[")
+		for i, item := range n.Array {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeNewline(buf, indent, level+1)
+			if err := item.writeJSON(buf, indent, level+1); err != nil {
+				return err
+			}
+		}
+		writeNewline(buf, indent, level)
+		buf.WriteString("]")
+	case String:
+		return writeJSONString(buf, n.String)
+	default:
+		value, err := n.FormatScalar()
+		if err != nil {
+			return err
+		}
+		buf.WriteString(value)
+	}
+
+	return nil
+}
+
+func writeNewline(buf *bytes.Buffer, indent string, level int) {
+	if indent == "" {
+		return
+	}
+	buf.WriteString("\n")
+	buf.WriteString(strings.Repeat(indent, level))
+}
+
+func writeJSONString(buf *bytes.Buffer, value string) error {
+	var encoded bytes.Buffer
+	enc := json.NewEncoder(&encoded)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
 )
 
 type Kind string
@@ -35,10 +37,16 @@ type Field struct {
 }
 
 func Parse(data []byte) (*Node, error) {
+	return ParseLimited(data, 0)
+}
+
+// ParseLimited parses data like Parse but fails when objects and arrays are
+// nested more than maxDepth levels deep. A maxDepth of zero means no limit.
+func ParseLimited(data []byte, maxDepth int) (*Node, error) {
 	dec := json.NewDecoder(bytes.NewReader(data))
 	dec.UseNumber()
 
-	node, err := parseValue(dec)
+	node, err := parseValue(dec, 0, maxDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +61,7 @@ func Parse(data []byte) (*Node, error) {
 	return node, nil
 }
 
-func parseValue(dec *json.Decoder) (*Node, error) {
+func parseValue(dec *json.Decoder, depth int, maxDepth int) (*Node, error) {
 	tok, err := dec.Token()
 	if err != nil {
 		return nil, err
@@ -61,6 +69,11 @@ func parseValue(dec *json.Decoder) (*Node, error) {
 
 	switch v := tok.(type) {
 	case json.Delim:
+		depth++
+		if maxDepth > 0 && depth > maxDepth {
+			return nil, diagnostics.New("nesting_too_deep", -1, "", "JSON input is nested more than %d levels deep", maxDepth)
+		}
+
 		switch v {
 		case '{':
 			fields := make([]Field, 0)
@@ -75,7 +88,7 @@ func parseValue(dec *json.Decoder) (*Node, error) {
 					return nil, fmt.Errorf("object key must be a string")
 				}
 
-				value, err := parseValue(dec)
+				value, err := parseValue(dec, depth, maxDepth)
 				if err != nil {
 					return nil, err
 				}
@@ -95,7 +108,7 @@ func parseValue(dec *json.Decoder) (*Node, error) {
 		case '[':
 			items := make([]*Node, 0)
 			for dec.More() {
-				item, err := parseValue(dec)
+				item, err := parseValue(dec, depth, maxDepth)
 				if err != nil {
 					return nil, err
 				}
@@ -0,0 +1,49 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	inlineLink  = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	escapedChar = regexp.MustCompile(`\\([[:punct:]])`)
+)
+
+// Anchors assigns the anchors GitHub generates for headings. Headings must be
+// added in document order so that duplicates get the same numeric suffixes.
+type Anchors map[string]int
+
+// Add returns the anchor for the next heading with the given Markdown text.
+// The text is lower-cased, punctuation other than hyphens and underscores is
+// dropped, and spaces become hyphens. A heading whose anchor is already taken
+// gets a numeric suffix.
+func (a Anchors) Add(text string) string {
+	text = escapedChar.ReplaceAllString(text, "$1")
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), unicode.Is(unicode.Mn, r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	slug := b.String()
+
+	count := a[slug]
+	a[slug] = count + 1
+	if count == 0 {
+		return slug
+	}
+	return slug + "-" + strconv.Itoa(count)
+}
+
+// LinkText replaces each inline link or image in text with its link text, as
+// GitHub does when it builds an anchor or a table of contents entry.
+func LinkText(text string) string {
+	return inlineLink.ReplaceAllString(text, "$1")
+}
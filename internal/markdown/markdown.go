@@ -0,0 +1,414 @@
+// Package markdown serializes an ast.Document as GitHub-flavored Markdown.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// plainYAMLKey matches keys that can be written without quotes. Keys that YAML
+// would resolve to booleans or null are quoted even when they match.
+var plainYAMLKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+var reservedYAMLWords = []string{"true", "false", "yes", "no", "on", "off", "y", "n", "null"}
+
+// Serializer writes blocks as Markdown for the engine.
+type Serializer struct{}
+
+func (Serializer) Block(block ast.Block) []string {
+	return Block(block)
+}
+
+func (Serializer) End() ([]string, error) {
+	return nil, nil
+}
+
+// Render returns the document as Markdown, without a trailing newline.
+func Render(doc *ast.Document) string {
+	return strings.Join(Lines(doc.Blocks), "\n")
+}
+
+// Lines returns the Markdown lines of consecutive blocks, separated by a blank
+// line. Blocks that produce no lines are skipped.
+func Lines(blocks []ast.Block) []string {
+	return markup.BlockLines(blocks, Block)
+}
+
+// Block returns the Markdown lines of one block. An element may itself
+// contain line breaks when a value does.
+func Block(block ast.Block) []string {
+	switch b := block.(type) {
+	case *ast.Heading:
+		return []string{strings.Repeat("#", b.Level) + " " + Inlines(b.Inlines)}
+	case *ast.Paragraph:
+		return paragraphLines(b.Inlines)
+	case *ast.List:
+		return listLines(b)
+	case *ast.Table:
+		return tableLines(b)
+	case *ast.CodeBlock:
+		return fenceLines(b.Language, b.Content)
+	case *ast.BlockQuote:
+		return quoteLines(Lines(b.Blocks))
+	case *ast.Alert:
+		return quoteLines(append([]string{fmt.Sprintf("[!%s]", b.Kind)}, Lines(b.Blocks)...))
+	case *ast.Details:
+		return detailsLines(b)
+	case *ast.DefinitionList:
+		return definitionListLines(b)
+	case *ast.ThematicBreak:
+		return []string{"---"}
+	case *ast.FrontMatter:
+		return frontMatterLines(b)
+	case *ast.RawBlock:
+		return slices.Clone(b.Lines)
+	default:
+		panic(fmt.Sprintf("markdown: unsupported block %T", block))
+	}
+}
+
+// Inlines returns inline content as Markdown on a single line, except where a
+// LineBreak or a value contains a line break.
+func Inlines(inlines []ast.Inline) string {
+	return strings.Join(paragraphLines(inlines), "\n")
+}
+
+// paragraphLines renders inlines and splits them at each hard line break,
+// ending every line but the last with a backslash.
+func paragraphLines(inlines []ast.Inline) []string {
+	lines := make([]string, 0, 1)
+	var line strings.Builder
+	for _, inline := range inlines {
+		if _, ok := inline.(*ast.LineBreak); ok {
+			lines = append(lines, line.String()+"\\")
+			line.Reset()
+			continue
+		}
+		line.WriteString(inlineMarkdown(inline, false))
+	}
+	if line.Len() > 0 || len(lines) > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+func inlineMarkdown(inline ast.Inline, inLink bool) string {
+	switch n := inline.(type) {
+	case *ast.Text:
+		if inLink {
+			return escapeLinkText(n.Value)
+		}
+		return escapeMarkdown(n.Value)
+	case *ast.Raw:
+		return n.Value
+	case *ast.Strong:
+		return "**" + joinInlines(n.Inlines, inLink) + "**"
+	case *ast.Code:
+		if n.Value == "" {
+			return ""
+		}
+		return formatCodeSpan(n.Value)
+	case *ast.Link:
+		return fmt.Sprintf("[%s](%s)", joinInlines(n.Inlines, true), n.URL)
+	case *ast.Image:
+		return fmt.Sprintf("![%s](%s)", escapeLinkText(n.Alt), n.URL)
+	case *ast.LineBreak:
+		return "\\\n"
+	default:
+		panic(fmt.Sprintf("markdown: unsupported inline %T", inline))
+	}
+}
+
+func joinInlines(inlines []ast.Inline, inLink bool) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		b.WriteString(inlineMarkdown(inline, inLink))
+	}
+	return b.String()
+}
+
+// listLines renders one bullet per item, indenting nested blocks by two
+// spaces beneath their item.
+func listLines(list *ast.List) []string {
+	lines := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		// Continuation lines of multi-line text are indented like nested
+		// blocks, so that they stay within the item.
+		for i, line := range strings.Split(Inlines(item.Inlines), "\n") {
+			if i == 0 {
+				lines = append(lines, "- "+line)
+			} else {
+				lines = append(lines, strings.TrimRight("  "+line, " "))
+			}
+		}
+		for _, block := range item.Blocks {
+			for _, line := range Block(block) {
+				lines = append(lines, "  "+line)
+			}
+		}
+	}
+	return lines
+}
+
+// tableLines renders a GitHub-flavored Markdown table.
+func tableLines(table *ast.Table) []string {
+	lines := make([]string, 0, len(table.Rows)+2)
+	lines = append(lines, tableRow(table.Header))
+
+	separator := make([]string, len(table.Header))
+	for i := range table.Header {
+		column := ast.AlignDefault
+		if i < len(table.Align) {
+			column = table.Align[i]
+		}
+		switch column {
+		case ast.AlignLeft:
+			separator[i] = ":---"
+		case ast.AlignCenter:
+			separator[i] = ":---:"
+		case ast.AlignRight:
+			separator[i] = "---:"
+		default:
+			separator[i] = "---"
+		}
+	}
+	lines = append(lines, "| "+strings.Join(separator, " | ")+" |")
+
+	for _, row := range table.Rows {
+		lines = append(lines, tableRow(row))
+	}
+	return lines
+}
+
+func tableRow(cells []*ast.Cell) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = escapeTableCell(Inlines(cell.Inlines))
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+// escapeTableCell keeps a value inside a single table cell by escaping pipes
+// and replacing line breaks with <br>.
+func escapeTableCell(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+// fenceLines wraps content in a fenced code block. The fence is always longer
+// than the longest run of backticks in the content so the content cannot close
+// the block early.
+func fenceLines(language string, content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+
+	fence := strings.Repeat("`", max(3, longestBacktickRun(content)+1))
+
+	lines := []string{fence + language}
+	if content != "" {
+		lines = append(lines, strings.Split(content, "\n")...)
+	}
+	return append(lines, fence)
+}
+
+func longestBacktickRun(value string) int {
+	longest, run := 0, 0
+	for _, r := range value {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	return longest
+}
+
+// formatCodeSpan wraps value in a code span delimited by more backticks than
+// the longest run inside it, padding with spaces when the value begins or ends
+// with a backtick.
+func formatCodeSpan(value string) string {
+	fence := strings.Repeat("`", longestBacktickRun(value)+1)
+	if strings.HasPrefix(value, "`") || strings.HasSuffix(value, "`") {
+		value = " " + value + " "
+	}
+	return fence + value + fence
+}
+
+// quoteLines prefixes every line with the Markdown blockquote marker, leaving
+// a bare marker on blank lines so the quote is not split in two.
+func quoteLines(lines []string) []string {
+	quoted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line == "" {
+			quoted = append(quoted, ">")
+			continue
+		}
+		quoted = append(quoted, "> "+line)
+	}
+	return quoted
+}
+
+// detailsLines renders a collapsible section. GitHub only renders Markdown
+// inside <details> when it is separated from the surrounding HTML tags by
+// blank lines.
+func detailsLines(details *ast.Details) []string {
+	body := Lines(details.Blocks)
+
+	lines := []string{"<details>", "<summary>" + html.EscapeString(details.Summary) + "</summary>"}
+	if len(body) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, body...)
+		lines = append(lines, "")
+	}
+	return append(lines, "</details>")
+}
+
+// definitionListLines renders an HTML <dl>, since Markdown has no definition
+// list syntax. All text is HTML-escaped.
+func definitionListLines(list *ast.DefinitionList) []string {
+	lines := []string{"<dl>"}
+	for _, item := range list.Items {
+		var value strings.Builder
+		for _, inline := range item.Inlines {
+			value.WriteString(inlineHTML(inline))
+		}
+
+		lines = append(lines,
+			"<dt>"+html.EscapeString(item.Term)+"</dt>",
+			"<dd>"+strings.ReplaceAll(value.String(), "\n", "<br>")+"</dd>",
+		)
+	}
+	return append(lines, "</dl>")
+}
+
+func inlineHTML(inline ast.Inline) string {
+	switch n := inline.(type) {
+	case *ast.Text:
+		return html.EscapeString(n.Value)
+	case *ast.Raw:
+		return html.EscapeString(n.Value)
+	case *ast.Strong:
+		var b strings.Builder
+		for _, child := range n.Inlines {
+			b.WriteString(inlineHTML(child))
+		}
+		return "<strong>" + b.String() + "</strong>"
+	case *ast.Code:
+		return "<code>" + html.EscapeString(n.Value) + "</code>"
+	case *ast.Link:
+		var b strings.Builder
+		for _, child := range n.Inlines {
+			b.WriteString(inlineHTML(child))
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(n.URL), b.String())
+	case *ast.Image:
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, html.EscapeString(n.URL), html.EscapeString(n.Alt))
+	case *ast.LineBreak:
+		return "<br>"
+	default:
+		panic(fmt.Sprintf("markdown: unsupported inline %T", inline))
+	}
+}
+
+// frontMatterLines renders YAML front matter. Strings are double-quoted so
+// that no value can be misread as another type or break the surrounding
+// document. Numbers, booleans, and null keep their JSON text, which YAML reads
+// as the same type.
+func frontMatterLines(frontMatter *ast.FrontMatter) []string {
+	lines := []string{"---"}
+	for _, field := range frontMatter.Fields {
+		value := field.Value
+		if field.IsString {
+			value = quoteYAML(value)
+		}
+		lines = append(lines, formatYAMLKey(field.Key)+": "+value)
+	}
+	return append(lines, "---")
+}
+
+func formatYAMLKey(key string) string {
+	if plainYAMLKey.MatchString(key) && !slices.Contains(reservedYAMLWords, strings.ToLower(key)) {
+		return key
+	}
+	return quoteYAML(key)
+}
+
+func quoteYAML(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case 0x85, 0x2028, 0x2029, 0xFEFF:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			if r < 0x20 || r == 0x7F {
+				fmt.Fprintf(&b, `\x%02X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func escapeLinkText(text string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		"[", "\\[",
+		"]", "\\]",
+		"\r\n", " ",
+		"\n", " ",
+		"\r", " ",
+	)
+	return replacer.Replace(text)
+}
+
+// escapeMarkdown backslash-escapes the characters that would otherwise be
+// interpreted as Markdown syntax within a line of plain text.
+func escapeMarkdown(line string) string {
+	var b strings.Builder
+	for _, r := range line {
+		if strings.ContainsRune("\\`*_[]<>|&", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	escaped := b.String()
+
+	// Characters that only start a block at the beginning of a line.
+	trimmed := strings.TrimLeft(escaped, " ")
+	indent := escaped[:len(escaped)-len(trimmed)]
+	switch {
+	case trimmed == "":
+		return escaped
+	case strings.ContainsRune("#+-=", rune(trimmed[0])):
+		return indent + "\\" + trimmed
+	}
+
+	digits := strings.IndexFunc(trimmed, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits > 0 && (trimmed[digits] == '.' || trimmed[digits] == ')') {
+		return indent + trimmed[:digits] + "\\" + trimmed[digits:]
+	}
+
+	return escaped
+}
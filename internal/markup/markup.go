@@ -0,0 +1,83 @@
+// Package markup holds the pieces that the serializers of the output formats
+// share, so that each is written and fixed in one place.
+package markup
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// LinkSchemes lists the URL schemes written as links.
+var LinkSchemes = []string{"http", "https", "mailto"}
+
+// BlockLines returns the lines that block writes for each of blocks,
+// separated by a blank line. Blocks that write nothing are skipped.
+func BlockLines(blocks []ast.Block, block func(ast.Block) []string) []string {
+	lines := make([]string, 0)
+	for _, b := range blocks {
+		lines = AppendBlock(lines, block(b))
+	}
+	return lines
+}
+
+// AppendBlock appends the lines of one rendered block, separating it from any
+// earlier output with a blank line so consecutive blocks do not merge.
+func AppendBlock(lines []string, block []string) []string {
+	if len(block) == 0 {
+		return lines
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return append(lines, block...)
+}
+
+// AlertTitle capitalizes the alert kind, so "WARNING" becomes "Warning".
+func AlertTitle(kind string) string {
+	if kind == "" {
+		return ""
+	}
+	return kind[:1] + strings.ToLower(kind[1:])
+}
+
+// IsLinkScheme reports whether scheme is one of LinkSchemes, in any case.
+func IsLinkScheme(scheme string) bool {
+	return slices.Contains(LinkSchemes, strings.ToLower(scheme))
+}
+
+// LinkURL returns the URL with each character in reserved percent-encoded,
+// when it is absolute and uses one of LinkSchemes. Formats pass the
+// characters that would end a link in their markup.
+func LinkURL(value string, reserved string) (string, bool) {
+	parsed, err := url.Parse(value)
+	if err != nil || !IsLinkScheme(parsed.Scheme) {
+		return "", false
+	}
+	return percentEncode(value, reserved), true
+}
+
+// ImageURL returns the URL as LinkURL does, when it also addresses an image
+// rather than a mailbox.
+func ImageURL(value string, reserved string) (string, bool) {
+	parsed, err := url.Parse(value)
+	if err != nil || strings.EqualFold(parsed.Scheme, "mailto") {
+		return "", false
+	}
+	return LinkURL(value, reserved)
+}
+
+func percentEncode(value string, reserved string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(reserved, r) {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package plaintext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/columns"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+var (
+	// escapeSequence matches ANSI CSI and OSC escape sequences, which are
+	// removed whole so that no printable remainder is left behind.
+	escapeSequence = regexp.MustCompile("\x1b\\[[0-?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+	codeFence     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	atxHeading    = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	quoteMarker   = regexp.MustCompile(`^ {0,3}> ?`)
+	thematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:\* *){3,}|(?:- *){3,}|(?:_ *){3,})$`)
+	delimiterRow  = regexp.MustCompile(`^ {0,3}\|? *:?-+:? *(?:\| *:?-+:? *)*\|? *$`)
+
+	// literal matches the code spans and backslash escapes whose content is
+	// written as is.
+	literal     = regexp.MustCompile("``(.+?)``|`([^`]+)`|\\\\([!-/:-@\\[-`{-~])")
+	placeholder = regexp.MustCompile("\x00([0-9]+)\x00")
+
+	image    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	link     = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	autolink = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	htmlTag  = regexp.MustCompile(`</?[A-Za-z][A-Za-z0-9-]*(?:\s[^>]*)?/?>`)
+	emphasis = []*regexp.Regexp{
+		regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`),
+		regexp.MustCompile(`__(\S(?:.*?\S)?)__`),
+		regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`),
+		regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`),
+		regexp.MustCompile(`\b_(\S(?:.*?\S)?)_\b`),
+	}
+)
+
+// clean removes escape sequences and control characters other than line
+// breaks and tabs, which a terminal would interpret rather than display.
+func clean(value string) string {
+	value = escapeSequence.ReplaceAllString(value, "")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// rawLines returns the lines of Markdown source as plain text. Heading and
+// quote markers, thematic breaks, and inline formatting are removed, fenced
+// code is indented like a code block, and tables are laid out in columns.
+// List markers are kept.
+func rawLines(source []string) []string {
+	lines := make([]string, 0, len(source))
+	fence := ""
+	for i := 0; i < len(source); i++ {
+		line := clean(source[i])
+
+		if match := codeFence.FindStringSubmatch(line); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+				continue
+			case match[1][0] == fence[0] && len(match[1]) >= len(fence):
+				fence = ""
+				continue
+			}
+		}
+		if fence != "" {
+			lines = append(lines, indentLines([]string{line}, indent+indent)...)
+			continue
+		}
+
+		if i+1 < len(source) && strings.Contains(line, "|") && strings.Contains(source[i+1], "|") && delimiterRow.MatchString(source[i+1]) {
+			end := i + 2
+			for end < len(source) && strings.Contains(source[end], "|") {
+				end++
+			}
+			lines = append(lines, tableLines(source[i], source[i+1], source[i+2:end])...)
+			i = end - 1
+			continue
+		}
+
+		lines = append(lines, rawLine(line))
+	}
+	return lines
+}
+
+// rawLine returns one line of Markdown source outside a table or fenced code
+// as plain text.
+func rawLine(line string) string {
+	prefix := ""
+	for {
+		marker := quoteMarker.FindString(line)
+		if marker == "" {
+			break
+		}
+		prefix += indent
+		line = line[len(marker):]
+	}
+
+	switch {
+	case thematicBreak.MatchString(line):
+		line = rule
+	case atxHeading.MatchString(line):
+		line = rawInlines(atxHeading.FindStringSubmatch(line)[1])
+	default:
+		line = rawInlines(line)
+	}
+	return strings.TrimRight(prefix+line, " ")
+}
+
+// tableLines lays out a Markdown table in columns.
+func tableLines(header string, delimiter string, rows []string) []string {
+	align := make([]ast.Alignment, 0)
+	for _, cell := range tableCells(delimiter) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			align = append(align, ast.AlignCenter)
+		case strings.HasSuffix(cell, ":"):
+			align = append(align, ast.AlignRight)
+		default:
+			align = append(align, ast.AlignDefault)
+		}
+	}
+
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = tableCells(row)
+	}
+	return columns.Lines(tableCells(header), cells, align)
+}
+
+// tableCells splits a table row at the pipes that are not escaped.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(clean(row))
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = strings.TrimSuffix(row, "|")
+	}
+
+	cells := make([]string, 0)
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch row[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, rawInlines(strings.TrimSpace(row[start:i])))
+			start = i + 1
+		}
+	}
+	return append(cells, rawInlines(strings.TrimSpace(row[start:])))
+}
+
+// rawInlines removes inline Markdown from a line of source. Links and images
+// are written as by Inlines.
+func rawInlines(line string) string {
+	// Code spans and escaped characters are set aside so that nothing inside
+	// them is taken for formatting. Control characters have been removed, so
+	// the placeholders cannot occur in the source.
+	literals := make([]string, 0)
+	line = literal.ReplaceAllStringFunc(line, func(match string) string {
+		parts := literal.FindStringSubmatch(match)
+		literals = append(literals, parts[1]+parts[2]+parts[3])
+		return "\x00" + strconv.Itoa(len(literals)-1) + "\x00"
+	})
+
+	line = image.ReplaceAllStringFunc(line, func(match string) string {
+		parts := image.FindStringSubmatch(match)
+		return withURL(parts[1], parts[2])
+	})
+	line = link.ReplaceAllStringFunc(line, func(match string) string {
+		parts := link.FindStringSubmatch(match)
+		return withURL(parts[1], parts[2])
+	})
+	line = autolink.ReplaceAllString(line, "$1")
+	line = htmlTag.ReplaceAllString(line, "")
+	for _, pattern := range emphasis {
+		line = pattern.ReplaceAllString(line, "$1")
+	}
+
+	return placeholder.ReplaceAllStringFunc(line, func(match string) string {
+		index, _ := strconv.Atoi(placeholder.FindStringSubmatch(match)[1])
+		return literals[index]
+	})
+}
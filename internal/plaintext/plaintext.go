@@ -0,0 +1,176 @@
+// Package plaintext serializes an ast.Document as plain text for terminals
+// and logs. No markup is written: headings are underlined, list items and
+// nested content are indented, and tables are columns aligned by display
+// width. Markdown source is written without its syntax, and control
+// characters are removed from every value.
+package plaintext
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/columns"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// indent is the indentation of each level of list items and of the content
+// of quotes, alerts, and details.
+const indent = "  "
+
+// rule is the line written for a thematic break.
+var rule = strings.Repeat("-", 40)
+
+// Serializer writes blocks as plain text.
+type Serializer struct{}
+
+func (Serializer) Block(block ast.Block) []string {
+	return Block(block)
+}
+
+func (Serializer) End() ([]string, error) {
+	return nil, nil
+}
+
+// Block returns the plain text lines of one block.
+func Block(block ast.Block) []string {
+	switch b := block.(type) {
+	case *ast.Heading:
+		return headingLines(b)
+	case *ast.Paragraph:
+		return strings.Split(Inlines(b.Inlines), "\n")
+	case *ast.List:
+		return listLines(b, 1)
+	case *ast.Table:
+		rows := make([][]string, len(b.Rows))
+		for i, row := range b.Rows {
+			rows[i] = cellTexts(row)
+		}
+		return columns.Lines(cellTexts(b.Header), rows, b.Align)
+	case *ast.CodeBlock:
+		content := clean(strings.ReplaceAll(b.Content, "\r\n", "\n"))
+		return indentLines(strings.Split(strings.TrimSuffix(content, "\n"), "\n"), indent+indent)
+	case *ast.BlockQuote:
+		return indentLines(markup.BlockLines(b.Blocks, Block), indent)
+	case *ast.Alert:
+		return append([]string{markup.AlertTitle(b.Kind) + ":"}, indentLines(markup.BlockLines(b.Blocks, Block), indent)...)
+	case *ast.Details:
+		return append([]string{clean(b.Summary)}, indentLines(markup.BlockLines(b.Blocks, Block), indent)...)
+	case *ast.DefinitionList:
+		lines := make([]string, 0, len(b.Items))
+		for _, item := range b.Items {
+			lines = append(lines, hangingLines(clean(item.Term)+": ", Inlines(item.Inlines))...)
+		}
+		return lines
+	case *ast.ThematicBreak:
+		return []string{rule}
+	case *ast.FrontMatter:
+		lines := make([]string, 0, len(b.Fields))
+		for _, field := range b.Fields {
+			lines = append(lines, hangingLines(clean(field.Key)+": ", clean(field.Value))...)
+		}
+		return lines
+	case *ast.RawBlock:
+		return rawLines(b.Lines)
+	default:
+		panic(fmt.Sprintf("plaintext: unsupported block %T", block))
+	}
+}
+
+// headingLines underlines a heading with "=" at level 1 and "-" below it.
+func headingLines(heading *ast.Heading) []string {
+	text := strings.ReplaceAll(Inlines(heading.Inlines), "\n", " ")
+	underline := "-"
+	if heading.Level == 1 {
+		underline = "="
+	}
+	return []string{text, strings.Repeat(underline, max(columns.Width(text), 1))}
+}
+
+// listLines writes each item indented by its level, without a bullet. Lines
+// after the first line of an item, and content nested beneath it, are
+// indented one level further.
+func listLines(list *ast.List, level int) []string {
+	prefix := strings.Repeat(indent, level)
+
+	lines := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		itemLines := strings.Split(Inlines(item.Inlines), "\n")
+		lines = append(lines, strings.TrimRight(prefix+itemLines[0], " "))
+		lines = append(lines, indentLines(itemLines[1:], prefix+indent)...)
+		for _, block := range item.Blocks {
+			if nested, ok := block.(*ast.List); ok {
+				lines = append(lines, listLines(nested, level+1)...)
+				continue
+			}
+			lines = append(lines, indentLines(Block(block), prefix+indent)...)
+		}
+	}
+	return lines
+}
+
+// hangingLines writes value after lead, indenting any further lines of value.
+func hangingLines(lead string, value string) []string {
+	lines := strings.Split(value, "\n")
+	return append([]string{strings.TrimRight(lead+lines[0], " ")}, indentLines(lines[1:], indent)...)
+}
+
+func cellTexts(cells []*ast.Cell) []string {
+	texts := make([]string, len(cells))
+	for i, cell := range cells {
+		texts[i] = strings.ReplaceAll(Inlines(cell.Inlines), "\n", " ")
+	}
+	return texts
+}
+
+// indentLines prefixes every non-empty line.
+func indentLines(lines []string, prefix string) []string {
+	indented := make([]string, len(lines))
+	for i, line := range lines {
+		if line != "" {
+			indented[i] = prefix + line
+		}
+	}
+	return indented
+}
+
+// Inlines returns inline content as plain text. A link is followed by its
+// URL in parentheses unless the URL is a fragment or the text already shows
+// it. Raw values are written as they are, apart from control characters,
+// because JSON values are rendered as Raw and are rarely meant as Markdown.
+func Inlines(inlines []ast.Inline) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		switch n := inline.(type) {
+		case *ast.Text:
+			b.WriteString(clean(n.Value))
+		case *ast.Raw:
+			b.WriteString(clean(n.Value))
+		case *ast.Strong:
+			b.WriteString(Inlines(n.Inlines))
+		case *ast.Code:
+			b.WriteString(clean(n.Value))
+		case *ast.Link:
+			text := Inlines(n.Inlines)
+			b.WriteString(withURL(text, clean(n.URL)))
+		case *ast.Image:
+			b.WriteString(withURL(clean(n.Alt), clean(n.URL)))
+		case *ast.LineBreak:
+			b.WriteString("\n")
+		default:
+			panic(fmt.Sprintf("plaintext: unsupported inline %T", inline))
+		}
+	}
+	return b.String()
+}
+
+func withURL(text string, url string) string {
+	switch {
+	case text == "":
+		return url
+	case url == "" || url == text || url == "mailto:"+text || strings.HasPrefix(url, "#"):
+		return text
+	default:
+		return text + " (" + url + ")"
+	}
+}
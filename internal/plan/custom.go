@@ -0,0 +1,97 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	customMu      sync.RWMutex
+	customOptions = map[string][]string{}
+)
+
+// RegisterOptions declares the options accepted by the custom directive op in
+// addition to op and path. Directives with that op are then decoded into
+// Directive.Options instead of the built-in fields.
+func RegisterOptions(op string, options []string) {
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	customOptions[op] = slices.Clone(options)
+}
+
+func lookupOptions(op string) ([]string, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+
+	options, ok := customOptions[op]
+	return options, ok
+}
+
+// UnmarshalJSON decodes a directive, rejecting unknown members. Built-in
+// directives accept the members of Directive. Custom directives accept op,
+// path, and their registered options.
+func (d *Directive) UnmarshalJSON(data []byte) error {
+	var header struct {
+		Op string `json:"op"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	if options, ok := lookupOptions(header.Op); ok {
+		return d.unmarshalCustom(data, options)
+	}
+
+	// The decoder does not carry DisallowUnknownFields into UnmarshalJSON, so
+	// built-in directives are decoded strictly here. The local type has no
+	// methods, which keeps Decode from calling back into UnmarshalJSON.
+	type directive Directive
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var decoded directive
+	if err := dec.Decode(&decoded); err != nil {
+		return err
+	}
+	*d = Directive(decoded)
+	return nil
+}
+
+func (d *Directive) unmarshalCustom(data []byte, options []string) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	decoded := Directive{Options: make(map[string]json.RawMessage)}
+	for _, name := range names {
+		value := members[name]
+		switch {
+		case name == "op":
+			if err := json.Unmarshal(value, &decoded.Op); err != nil {
+				return err
+			}
+		case name == "path":
+			if err := json.Unmarshal(value, &decoded.Path); err != nil {
+				return err
+			}
+		case slices.Contains(options, name):
+			decoded.Options[name] = value
+		default:
+			return fmt.Errorf("json: unknown field %q", name)
+		}
+	}
+
+	*d = decoded
+	return nil
+}
@@ -21,7 +21,7 @@ func Generate(root *jsondoc.Node) (*Plan, error) {
 		}
 
 		return &Plan{
-			Version: 1,
+			Version: Version,
 			Directives: []Directive{
 				{
 					Op:     "named_bullets",
@@ -38,7 +38,7 @@ func Generate(root *jsondoc.Node) (*Plan, error) {
 		}
 
 		return &Plan{
-			Version: 1,
+			Version: Version,
 			Directives: []Directive{
 				{
 					Op:   "bullet_list",
@@ -6,20 +6,62 @@ import (
 	"io"
 )
 
+// Version is the only plan format version that this release reads and writes.
+const Version = 1
+
 type Plan struct {
 	Version    int         `json:"version"`
 	Directives []Directive `json:"directives"`
 }
 
 type Directive struct {
-	Op     string  `json:"op"`
-	Path   string  `json:"path"`
-	Fields []Field `json:"fields,omitempty"`
+	Op           string            `json:"op"`
+	Path         string            `json:"path"`
+	Fields       []Field           `json:"fields,omitempty"`
+	Depth        int               `json:"depth,omitempty"`
+	Label        string            `json:"label,omitempty"`
+	Language     string            `json:"language,omitempty"`
+	Kind         string            `json:"kind,omitempty"`
+	KindPath     string            `json:"kind_path,omitempty"`
+	Kinds        map[string]string `json:"kinds,omitempty"`
+	Summary      string            `json:"summary,omitempty"`
+	SummaryPath  string            `json:"summary_path,omitempty"`
+	Directives   []Directive       `json:"directives,omitempty"`
+	Text         string            `json:"text,omitempty"`
+	TextPath     string            `json:"text_path,omitempty"`
+	Markdown     bool              `json:"markdown,omitempty"`
+	MinLevel     int               `json:"min_level,omitempty"`
+	MaxLevel     int               `json:"max_level,omitempty"`
+	Layout       string            `json:"layout,omitempty"`
+	SortBy       string            `json:"sort_by,omitempty"`
+	SortOrder    string            `json:"sort_order,omitempty"`
+	HeaderPath   string            `json:"header_path,omitempty"`
+	GroupBy      string            `json:"group_by,omitempty"`
+	GroupOrder   string            `json:"group_order,omitempty"`
+	HeadingLevel int               `json:"heading_level,omitempty"`
+	Limit        int               `json:"limit,omitempty"`
+	Offset       int               `json:"offset,omitempty"`
+	MoreText     string            `json:"more_text,omitempty"`
+
+	// Options holds the raw options of a custom directive, keyed by the names
+	// declared when its op was registered. It is always empty for built-in
+	// directives.
+	Options map[string]json.RawMessage `json:"-"`
 }
 
 type Field struct {
-	Path  string `json:"path"`
-	Label string `json:"label"`
+	Path        string          `json:"path"`
+	Label       string          `json:"label"`
+	Link        string          `json:"link,omitempty"`
+	Value       json.RawMessage `json:"value,omitempty"`
+	Align       string          `json:"align,omitempty"`
+	Aggregate   string          `json:"aggregate,omitempty"`
+	Missing     string          `json:"missing,omitempty"`
+	Placeholder string          `json:"placeholder,omitempty"`
+	NonScalar   string          `json:"non_scalar,omitempty"`
+	Inline      bool            `json:"inline,omitempty"`
+	Separator   string          `json:"separator,omitempty"`
+	Code        bool            `json:"code,omitempty"`
 }
 
 func Parse(data []byte) (*Plan, error) {
@@ -0,0 +1,251 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// Slack rejects header text longer than maxHeaderLength and section text
+// longer than maxSectionLength characters, and messages with more than
+// maxBlocks blocks.
+const (
+	maxHeaderLength  = 150
+	maxSectionLength = 3000
+	maxBlocks        = 50
+)
+
+// kitBlock is one Block Kit layout block.
+type kitBlock struct {
+	Type     string   `json:"type"`
+	Text     *kitText `json:"text,omitempty"`
+	ImageURL string   `json:"image_url,omitempty"`
+	AltText  string   `json:"alt_text,omitempty"`
+}
+
+// kitText is a Block Kit text object.
+type kitText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// BlockKit writes a document as Block Kit JSON, an object with a "blocks"
+// array as accepted by chat.postMessage. Headings become header blocks,
+// rules become dividers, images on their own become image blocks, and other
+// blocks become mrkdwn sections. The JSON is written by End, once the
+// document is complete, and End fails when the document needs more blocks
+// than Slack accepts in one message.
+type BlockKit struct {
+	blocks []kitBlock
+}
+
+func (s *BlockKit) Block(block ast.Block) []string {
+	s.blocks = append(s.blocks, kitBlocks(block)...)
+	return nil
+}
+
+func (s *BlockKit) End() ([]string, error) {
+	blocks := s.blocks
+	if blocks == nil {
+		blocks = []kitBlock{}
+	}
+	if len(blocks) > maxBlocks {
+		return nil, diagnostics.New("output_too_large", -1, "", "Block Kit output has %d blocks, more than the %d Slack accepts in a message", len(blocks), maxBlocks)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(struct {
+		Blocks []kitBlock `json:"blocks"`
+	}{blocks}); err != nil {
+		return nil, fmt.Errorf("encode Block Kit JSON: %w", err)
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+func kitBlocks(block ast.Block) []kitBlock {
+	switch b := block.(type) {
+	case *ast.Heading:
+		text := strings.ReplaceAll(PlainText(b.Inlines), "\n", " ")
+		if text != "" && utf8.RuneCountInString(text) <= maxHeaderLength {
+			return []kitBlock{{Type: "header", Text: &kitText{Type: "plain_text", Text: text}}}
+		}
+	case *ast.ThematicBreak:
+		return []kitBlock{{Type: "divider"}}
+	case *ast.Paragraph:
+		if image, ok := imageBlock(b); ok {
+			return []kitBlock{image}
+		}
+	case *ast.Details:
+		blocks := sections([]string{"*" + escape(b.Summary) + "*"})
+		for _, child := range b.Blocks {
+			blocks = append(blocks, kitBlocks(child)...)
+		}
+		return blocks
+	}
+	return sections(Block(block))
+}
+
+// imageBlock returns an image block for a paragraph that holds only an image
+// with a web URL, which Slack requires for images.
+func imageBlock(paragraph *ast.Paragraph) (kitBlock, bool) {
+	if len(paragraph.Inlines) != 1 {
+		return kitBlock{}, false
+	}
+	image, ok := paragraph.Inlines[0].(*ast.Image)
+	if !ok {
+		return kitBlock{}, false
+	}
+	parsed, err := url.Parse(image.URL)
+	if err != nil || (strings.ToLower(parsed.Scheme) != "http" && strings.ToLower(parsed.Scheme) != "https") {
+		return kitBlock{}, false
+	}
+
+	alt := image.Alt
+	if alt == "" {
+		alt = image.URL
+	}
+	return kitBlock{Type: "image", ImageURL: image.URL, AltText: alt}, true
+}
+
+// sections returns mrkdwn sections holding lines, split between lines so
+// that no section exceeds the length Slack accepts. A code block split across
+// sections is closed at the end of one and reopened at the start of the next.
+// A line too long for any section is cut by cutLine.
+func sections(lines []string) []kitBlock {
+	blocks := make([]kitBlock, 0, 1)
+	var chunk []string
+	length := 0
+	fence := ""
+	add := func(line string) {
+		if len(chunk) > 0 {
+			length++
+		}
+		chunk = append(chunk, line)
+		length += utf8.RuneCountInString(line)
+	}
+	flush := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		if fence != "" {
+			chunk = append(chunk, fence)
+		}
+		blocks = append(blocks, kitBlock{Type: "section", Text: &kitText{Type: "mrkdwn", Text: strings.Join(chunk, "\n")}})
+		chunk, length = nil, 0
+		if fence != "" {
+			add(fence)
+		}
+	}
+	// room is the length left in the section for line and, inside a code
+	// block, the fence that closes the section.
+	room := func() int {
+		room := maxSectionLength - length
+		if len(chunk) > 0 {
+			room--
+		}
+		if fence != "" {
+			room -= utf8.RuneCountInString(fence) + 1
+		}
+		return room
+	}
+
+	for _, line := range strings.Split(strings.Join(lines, "\n"), "\n") {
+		isFence := isFenceLine(line)
+		if isFence && fence != "" {
+			// The closing fence takes the room reserved for it.
+			fence = ""
+			add(line)
+			continue
+		}
+
+		for utf8.RuneCountInString(line) > room() {
+			if len(chunk) > 0 && (fence == "" || len(chunk) > 1) {
+				flush()
+				continue
+			}
+			// The rest of a cut line keeps its quote markers.
+			cut := cutLine(line, room())
+			prefix := quotePrefix(line)
+			if len(prefix) >= cut {
+				prefix = ""
+			}
+			add(line[:cut])
+			flush()
+			line = prefix + line[cut:]
+		}
+		add(line)
+		if isFence {
+			fence = line
+		}
+	}
+	fence = ""
+	flush()
+	return blocks
+}
+
+// quotePrefix returns the quote markers at the start of line.
+func quotePrefix(line string) string {
+	rest := line
+	for strings.HasPrefix(rest, "> ") {
+		rest = rest[len("> "):]
+	}
+	return line[:len(line)-len(rest)]
+}
+
+// isFenceLine reports whether line opens or closes a code block, possibly
+// inside a quote.
+func isFenceLine(line string) bool {
+	return strings.TrimLeft(line, "> ") == "```"
+}
+
+// cutLine returns the byte offset at which to cut a line longer than max
+// characters: after the last space within max characters, or else at the last
+// position within them. It never cuts inside an escaped character such as
+// &amp; or a <url|text> link, unless one of those starts the line and is
+// longer than max on its own.
+func cutLine(line string, max int) int {
+	lastSafe, lastSpace := 0, 0
+	inEntity, inLink := false, false
+	count := 0
+	for i, r := range line {
+		if i > 0 && !inEntity && !inLink {
+			lastSafe = i
+			if line[i-1] == ' ' {
+				lastSpace = i
+			}
+		}
+		if count == max {
+			break
+		}
+
+		switch {
+		case r == '&' && !inLink:
+			inEntity = true
+		case r == ';' && inEntity:
+			inEntity = false
+		case r == '<':
+			inLink = true
+		case r == '>' && inLink:
+			inLink = false
+		}
+		count++
+	}
+
+	switch {
+	case lastSpace > 0:
+		return lastSpace
+	case lastSafe > 0:
+		return lastSafe
+	default:
+		return len(string([]rune(line)[:max]))
+	}
+}
@@ -0,0 +1,231 @@
+// Package slack serializes an ast.Document as Slack mrkdwn or as Block Kit
+// JSON. Slack renders neither headings nor tables, so headings become bold
+// lines and tables become column-aligned code blocks.
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/columns"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markup"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// alertEmoji maps each alert kind to the emoji that introduces it.
+var alertEmoji = map[string]string{
+	"NOTE":      ":information_source:",
+	"TIP":       ":bulb:",
+	"IMPORTANT": ":exclamation:",
+	"WARNING":   ":warning:",
+	"CAUTION":   ":rotating_light:",
+}
+
+// escaper escapes the characters that Slack reserves for its own markup.
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Serializer writes blocks as Slack mrkdwn. Front matter is written as bold
+// keys followed by their values.
+type Serializer struct{}
+
+func (Serializer) Block(block ast.Block) []string {
+	return Block(block)
+}
+
+func (Serializer) End() ([]string, error) {
+	return nil, nil
+}
+
+// Block returns the mrkdwn lines of one block.
+func Block(block ast.Block) []string {
+	switch b := block.(type) {
+	case *ast.Heading:
+		return []string{bold(b.Inlines)}
+	case *ast.Paragraph:
+		return strings.Split(inlines(b.Inlines), "\n")
+	case *ast.List:
+		return listLines(b, 0)
+	case *ast.Table:
+		return codeLines(strings.Join(tableLines(b), "\n"))
+	case *ast.CodeBlock:
+		return codeLines(b.Content)
+	case *ast.BlockQuote:
+		return quoteLines(markup.BlockLines(b.Blocks, Block))
+	case *ast.Alert:
+		title := strings.TrimSpace(alertEmoji[b.Kind] + " *" + markup.AlertTitle(b.Kind) + "*")
+		return quoteLines(append([]string{title}, markup.BlockLines(b.Blocks, Block)...))
+	case *ast.Details:
+		// Slack has no collapsible content, so the summary becomes a bold
+		// line above the content.
+		return append([]string{"*" + escape(b.Summary) + "*"}, markup.BlockLines(b.Blocks, Block)...)
+	case *ast.DefinitionList:
+		lines := make([]string, 0, len(b.Items))
+		for _, item := range b.Items {
+			lines = append(lines, "*"+escape(item.Term)+":* "+inlines(item.Inlines))
+		}
+		return lines
+	case *ast.ThematicBreak:
+		return []string{"───"}
+	case *ast.FrontMatter:
+		// Slack has no document metadata, so front matter is written like a
+		// definition list.
+		lines := make([]string, 0, len(b.Fields))
+		for _, field := range b.Fields {
+			lines = append(lines, strings.TrimRight("*"+escape(field.Key)+":* "+escape(field.Value), " "))
+		}
+		return lines
+	case *ast.RawBlock:
+		lines := make([]string, 0, len(b.Lines))
+		for _, line := range b.Lines {
+			lines = append(lines, escape(line))
+		}
+		return lines
+	default:
+		panic(fmt.Sprintf("slack: unsupported block %T", block))
+	}
+}
+
+// listLines writes one bullet per item, indenting nested lists by four
+// spaces per level. Slack has no list markup.
+func listLines(list *ast.List, level int) []string {
+	indent := strings.Repeat("    ", level)
+
+	lines := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		for i, line := range strings.Split(inlines(item.Inlines), "\n") {
+			if i == 0 {
+				lines = append(lines, indent+"• "+line)
+			} else {
+				lines = append(lines, strings.TrimRight(indent+"    "+line, " "))
+			}
+		}
+		for _, block := range item.Blocks {
+			if nested, ok := block.(*ast.List); ok {
+				lines = append(lines, listLines(nested, level+1)...)
+				continue
+			}
+			for _, line := range Block(block) {
+				lines = append(lines, indent+"    "+line)
+			}
+		}
+	}
+	return lines
+}
+
+// tableLines writes the table as plain text columns aligned by display
+// width.
+func tableLines(table *ast.Table) []string {
+	rows := make([][]string, len(table.Rows))
+	for i, row := range table.Rows {
+		rows[i] = cellTexts(row)
+	}
+	return columns.Lines(cellTexts(table.Header), rows, table.Align)
+}
+
+func cellTexts(cells []*ast.Cell) []string {
+	texts := make([]string, len(cells))
+	for i, cell := range cells {
+		texts[i] = strings.ReplaceAll(PlainText(cell.Inlines), "\n", " ")
+	}
+	return texts
+}
+
+// codeLines wraps content in a code block. Slack ignores the language.
+func codeLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	return strings.Split("```\n"+escape(content)+"\n```", "\n")
+}
+
+func quoteLines(lines []string) []string {
+	quoted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		quoted = append(quoted, strings.TrimRight("> "+line, " "))
+	}
+	return quoted
+}
+
+func inlines(inlines []ast.Inline) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		b.WriteString(inlineMrkdwn(inline))
+	}
+	return b.String()
+}
+
+func inlineMrkdwn(inline ast.Inline) string {
+	switch n := inline.(type) {
+	case *ast.Text:
+		return escape(n.Value)
+	case *ast.Raw:
+		return escape(n.Value)
+	case *ast.Strong:
+		return bold(n.Inlines)
+	case *ast.Code:
+		// Slack code spans cannot contain a backtick.
+		if n.Value == "" || strings.Contains(n.Value, "`") {
+			return escape(n.Value)
+		}
+		return "`" + escape(n.Value) + "`"
+	case *ast.Link:
+		target, ok := linkURL(n.URL)
+		if !ok {
+			return inlines(n.Inlines)
+		}
+		return "<" + target + "|" + strings.ReplaceAll(inlines(n.Inlines), "\n", " ") + ">"
+	case *ast.Image:
+		target, ok := linkURL(n.URL)
+		if !ok {
+			return escape(n.Alt)
+		}
+		return "<" + target + "|" + escape(n.Alt) + ">"
+	case *ast.LineBreak:
+		return "\n"
+	default:
+		panic(fmt.Sprintf("slack: unsupported inline %T", inline))
+	}
+}
+
+func bold(content []ast.Inline) string {
+	text := inlines(content)
+	if text == "" {
+		return ""
+	}
+	return "*" + text + "*"
+}
+
+// PlainText returns inline content without markup or escaping.
+func PlainText(inlines []ast.Inline) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		switch n := inline.(type) {
+		case *ast.Text:
+			b.WriteString(n.Value)
+		case *ast.Raw:
+			b.WriteString(n.Value)
+		case *ast.Strong:
+			b.WriteString(PlainText(n.Inlines))
+		case *ast.Code:
+			b.WriteString(n.Value)
+		case *ast.Link:
+			b.WriteString(PlainText(n.Inlines))
+		case *ast.Image:
+			b.WriteString(n.Alt)
+		case *ast.LineBreak:
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// linkURL returns the URL escaped for a Slack link, with the pipe that would
+// end it percent-encoded. Slack cannot resolve relative URLs, so only absolute
+// URLs with a link scheme are returned.
+func linkURL(value string) (string, bool) {
+	target, ok := markup.LinkURL(value, "|")
+	return escape(target), ok
+}
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
@@ -0,0 +1,184 @@
+// Package ast defines the document model that directives build and that each
+// output format serializes. A Document is a sequence of blocks, and blocks that
+// hold text do so as a sequence of inlines.
+//
+// Text in the model is never escaped for a particular format; each serializer
+// applies its own escaping. The one exception is Raw, which holds Markdown
+// source.
+package ast
+
+// Document is a rendered plan.
+type Document struct {
+	Blocks []Block
+}
+
+// Block is a node that starts on its own line, such as a heading or a table.
+// The implementations are the pointer types in this package.
+type Block interface {
+	block()
+}
+
+// Inline is a node inside a block, such as text or a link. The
+// implementations are the pointer types in this package.
+type Inline interface {
+	inline()
+}
+
+// Heading is a section heading. Level is between 1 and 6.
+type Heading struct {
+	Level   int
+	Inlines []Inline
+}
+
+// Paragraph is a paragraph of text. LineBreak inlines split it into lines.
+type Paragraph struct {
+	Inlines []Inline
+}
+
+// List is a bullet list.
+type List struct {
+	Items []*ListItem
+}
+
+// ListItem is one bullet. Blocks holds content nested beneath the bullet,
+// such as a sublist.
+type ListItem struct {
+	Inlines []Inline
+	Blocks  []Block
+}
+
+// Alignment is the horizontal alignment of a table column.
+type Alignment string
+
+const (
+	AlignDefault Alignment = ""
+	AlignLeft    Alignment = "left"
+	AlignCenter  Alignment = "center"
+	AlignRight   Alignment = "right"
+)
+
+// Table is a table with a header row. Align has one entry per column, or is
+// nil when every column uses the default alignment.
+type Table struct {
+	Header []*Cell
+	Align  []Alignment
+	Rows   [][]*Cell
+}
+
+// Cell is one table cell.
+type Cell struct {
+	Inlines []Inline
+}
+
+// CodeBlock is preformatted text, optionally tagged with a language.
+type CodeBlock struct {
+	Language string
+	Content  string
+}
+
+// BlockQuote is quoted content.
+type BlockQuote struct {
+	Blocks []Block
+}
+
+// Alert is a callout such as a note or a warning. Kind is one of NOTE, TIP,
+// IMPORTANT, WARNING, or CAUTION.
+type Alert struct {
+	Kind   string
+	Blocks []Block
+}
+
+// Details is collapsible content introduced by a summary line.
+type Details struct {
+	Summary string
+	Blocks  []Block
+}
+
+// DefinitionList is a list of terms, each with a definition.
+type DefinitionList struct {
+	Items []*Definition
+}
+
+// Definition is one term of a DefinitionList.
+type Definition struct {
+	Term    string
+	Inlines []Inline
+}
+
+// ThematicBreak is a horizontal rule.
+type ThematicBreak struct{}
+
+// FrontMatter is document metadata, written before any other block.
+type FrontMatter struct {
+	Fields []*MetadataField
+}
+
+// MetadataField is one FrontMatter entry. IsString reports whether Value is a
+// string rather than the JSON text of a number, boolean, or null.
+type MetadataField struct {
+	Key      string
+	Value    string
+	IsString bool
+}
+
+// RawBlock is Markdown source written as is, one element per line.
+type RawBlock struct {
+	Lines []string
+}
+
+// Text is plain text.
+type Text struct {
+	Value string
+}
+
+// Raw is inline Markdown source. JSON values are rendered as Raw so that
+// Markdown output passes any formatting they contain through unchanged.
+type Raw struct {
+	Value string
+}
+
+// Strong is strongly emphasized content.
+type Strong struct {
+	Inlines []Inline
+}
+
+// Code is inline code.
+type Code struct {
+	Value string
+}
+
+// Link is a hyperlink around its inlines.
+type Link struct {
+	URL     string
+	Inlines []Inline
+}
+
+// Image is an image with alternative text.
+type Image struct {
+	URL string
+	Alt string
+}
+
+// LineBreak is a hard line break within a paragraph.
+type LineBreak struct{}
+
+func (*Heading) block()        {}
+func (*Paragraph) block()      {}
+func (*List) block()           {}
+func (*Table) block()          {}
+func (*CodeBlock) block()      {}
+func (*BlockQuote) block()     {}
+func (*Alert) block()          {}
+func (*Details) block()        {}
+func (*DefinitionList) block() {}
+func (*ThematicBreak) block()  {}
+func (*FrontMatter) block()    {}
+func (*RawBlock) block()       {}
+
+func (*Text) inline()      {}
+func (*Raw) inline()       {}
+func (*Strong) inline()    {}
+func (*Code) inline()      {}
+func (*Link) inline()      {}
+func (*Image) inline()     {}
+func (*LineBreak) inline() {}
@@ -0,0 +1,256 @@
+package json2mdplan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/directives"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// Handler renders a custom directive. root is the JSON input and directive is
+// the plan entry with its declared options. ctx is the context passed to
+// Render; handlers that do a lot of work should stop when it is done.
+//
+// A handler must list in Result.Consumed the pointer of every scalar it
+// renders, because coverage is checked exactly as for built-in directives.
+// Errors of type *Error keep their code and path. Any other error is reported
+// with CodeError. Either way the error is attributed to the directive. Blocks
+// that cannot be written, such as nil blocks or inlines, are reported with
+// CodeInvalidPlan.
+type Handler interface {
+	Execute(ctx context.Context, root *Value, directive Directive) (*Result, error)
+}
+
+// HandlerFunc adapts a function to the Handler interface.
+type HandlerFunc func(ctx context.Context, root *Value, directive Directive) (*Result, error)
+
+// Execute calls f.
+func (f HandlerFunc) Execute(ctx context.Context, root *Value, directive Directive) (*Result, error) {
+	return f(ctx, root, directive)
+}
+
+// Directive is one custom directive from a plan.
+type Directive struct {
+	// Index is the position of the directive in the plan. Directives nested
+	// in a details directive have the index of the enclosing directive.
+	Index int
+	// Op is the registered op.
+	Op string
+	// Path is the directive path, which the handler resolves itself.
+	Path string
+	// Options holds the raw JSON of each declared option that the plan sets.
+	Options map[string]json.RawMessage
+}
+
+// Option decodes the named option into target and reports whether the plan
+// set it.
+func (d Directive) Option(name string, target any) (bool, error) {
+	raw, ok := d.Options[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return true, &Error{
+			Code:      CodeInvalidPlan,
+			Directive: d.Index,
+			Path:      d.Path,
+			Message:   fmt.Sprintf("directive %q is invalid: option %q: %s", d.Op, name, err.Error()),
+		}
+	}
+	return true, nil
+}
+
+// Result is the output of a custom directive.
+type Result struct {
+	// Blocks is the rendered output as document blocks.
+	Blocks []ast.Block
+	// Lines is rendered Markdown, one line per element, written after Blocks
+	// as an ast.RawBlock. Consecutive directives are separated by a blank
+	// line.
+	Lines []string
+	// Consumed lists the JSON Pointers of the scalars that were rendered.
+	Consumed []string
+	// Elided lists the JSON Pointers of scalars deliberately left out of the
+	// output. They count as covered.
+	Elided []string
+}
+
+// Register adds a custom directive op. options names the plan members the
+// directive accepts besides op and path; plans that set any other member on
+// the directive are rejected, as they are for built-in directives.
+//
+// Register is safe for concurrent use but is normally called from an init
+// function. It fails when op is empty or already registered, including when
+// op names a built-in directive, or when an option is op, path, or listed
+// twice.
+func Register(op string, options []string, handler Handler) error {
+	if op == "" {
+		return errors.New("directive op must not be empty")
+	}
+	if handler == nil {
+		return fmt.Errorf("directive %q handler must not be nil", op)
+	}
+	for i, option := range options {
+		if option == "op" || option == "path" {
+			return fmt.Errorf("directive %q option %q is reserved", op, option)
+		}
+		if slices.Contains(options[:i], option) {
+			return fmt.Errorf("directive %q option %q is listed more than once", op, option)
+		}
+	}
+
+	if err := directives.Register(op, customHandler{handler: handler}); err != nil {
+		return err
+	}
+	plan.RegisterOptions(op, options)
+	return nil
+}
+
+// customHandler adapts a public Handler to the internal handler interface.
+type customHandler struct {
+	handler Handler
+}
+
+func (h customHandler) Execute(ctx context.Context, root *jsondoc.Node, directiveIndex int, directive plan.Directive) (*directives.Result, error) {
+	result, err := h.handler.Execute(ctx, &Value{root: root, node: root}, Directive{
+		Index:   directiveIndex,
+		Op:      directive.Op,
+		Path:    directive.Path,
+		Options: directive.Options,
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return nil, err
+		}
+
+		var custom *Error
+		if errors.As(err, &custom) {
+			return nil, diagnostics.New(custom.Code, directiveIndex, custom.Path, "%s", custom.Message)
+		}
+		return nil, diagnostics.New(CodeError, directiveIndex, directive.Path, "%s", err.Error())
+	}
+	if result == nil {
+		return &directives.Result{}, nil
+	}
+
+	if problem := checkBlocks(result.Blocks); problem != "" {
+		return nil, diagnostics.New(CodeInvalidPlan, directiveIndex, directive.Path, "directive %q returned %s", directive.Op, problem)
+	}
+
+	blocks := slices.Clone(result.Blocks)
+	if len(result.Lines) > 0 {
+		blocks = append(blocks, &ast.RawBlock{Lines: result.Lines})
+	}
+
+	return &directives.Result{
+		Blocks:   blocks,
+		Consumed: result.Consumed,
+		Elided:   result.Elided,
+	}, nil
+}
+
+// alertKinds lists the values of ast.Alert.Kind.
+var alertKinds = []string{"NOTE", "TIP", "IMPORTANT", "WARNING", "CAUTION"}
+
+// checkBlocks describes the first block or inline in blocks that the
+// serializers cannot write, such as a nil block, or returns "" when there is
+// none.
+func checkBlocks(blocks []ast.Block) string {
+	for _, block := range blocks {
+		if problem := checkBlock(block); problem != "" {
+			return problem
+		}
+	}
+	return ""
+}
+
+func checkBlock(block ast.Block) string {
+	// Every implementation is a pointer type, so a nil pointer is as
+	// unwritable as a nil interface.
+	if block == nil || reflect.ValueOf(block).IsNil() {
+		return "a nil block"
+	}
+
+	switch b := block.(type) {
+	case *ast.Heading:
+		if b.Level < 1 || b.Level > 6 {
+			return fmt.Sprintf("a heading with level %d", b.Level)
+		}
+		return checkInlines(b.Inlines)
+	case *ast.Paragraph:
+		return checkInlines(b.Inlines)
+	case *ast.List:
+		for _, item := range b.Items {
+			if item == nil {
+				return "a nil list item"
+			}
+			if problem := checkInlines(item.Inlines); problem != "" {
+				return problem
+			}
+			if problem := checkBlocks(item.Blocks); problem != "" {
+				return problem
+			}
+		}
+	case *ast.Table:
+		for _, row := range append([][]*ast.Cell{b.Header}, b.Rows...) {
+			for _, cell := range row {
+				if cell == nil {
+					return "a nil table cell"
+				}
+				if problem := checkInlines(cell.Inlines); problem != "" {
+					return problem
+				}
+			}
+		}
+	case *ast.BlockQuote:
+		return checkBlocks(b.Blocks)
+	case *ast.Alert:
+		if !slices.Contains(alertKinds, b.Kind) {
+			return fmt.Sprintf("an alert with kind %q", b.Kind)
+		}
+		return checkBlocks(b.Blocks)
+	case *ast.Details:
+		return checkBlocks(b.Blocks)
+	case *ast.DefinitionList:
+		for _, item := range b.Items {
+			if item == nil {
+				return "a nil definition"
+			}
+			if problem := checkInlines(item.Inlines); problem != "" {
+				return problem
+			}
+		}
+	case *ast.FrontMatter:
+		if slices.Contains(b.Fields, nil) {
+			return "a nil front matter field"
+		}
+	}
+	return ""
+}
+
+func checkInlines(inlines []ast.Inline) string {
+	for _, inline := range inlines {
+		if inline == nil || reflect.ValueOf(inline).IsNil() {
+			return "a nil inline"
+		}
+		switch n := inline.(type) {
+		case *ast.Strong:
+			if problem := checkInlines(n.Inlines); problem != "" {
+				return problem
+			}
+		case *ast.Link:
+			if problem := checkInlines(n.Inlines); problem != "" {
+				return problem
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package json2mdplan
+
+import (
+	"context"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/engine"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markdown"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+// RenderDocument renders the JSON document with the plan and returns the
+// document model instead of Markdown, for programs that write their own
+// output format. Errors are reported as by Render, and MaxOutputBytes applies
+// to the output that Render would return in the selected format.
+func RenderDocument(ctx context.Context, jsonBytes []byte, planBytes []byte, opts RenderOptions) (*ast.Document, error) {
+	format, err := opts.engineFormat()
+	if err != nil {
+		return nil, err
+	}
+	root, parsedPlan, err := parseInputs(ctx, jsonBytes, planBytes, opts.InputLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation, err := engine.Evaluate(ctx, root, parsedPlan, opts.engineLimits(), format)
+	if err != nil {
+		return nil, wrapError(err, CodeError)
+	}
+	opts.report(evaluation.Elided)
+	return evaluation.Document, nil
+}
+
+// Markdown returns the document as Markdown, exactly as Render writes it.
+func Markdown(doc *ast.Document) string {
+	return markdown.Render(doc)
+}
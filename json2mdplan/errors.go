@@ -0,0 +1,107 @@
+package json2mdplan
+
+import (
+	"context"
+	"errors"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/diagnostics"
+)
+
+// Codes identify the kind of an Error. They are part of the compatibility
+// promise: a code is never renamed or reused for a different condition.
+const (
+	// CodeError is used for failures that have no more specific code.
+	CodeError = "error"
+	// CodeInvalidJSON means the JSON input could not be parsed.
+	CodeInvalidJSON = "invalid_json"
+	// CodeInvalidPlan means the plan could not be parsed or a directive is
+	// malformed, including when it sets an option that its op does not read.
+	CodeInvalidPlan = "invalid_plan"
+	// CodeUnsupportedVersion means the plan version is not PlanVersion.
+	CodeUnsupportedVersion = "unsupported_version"
+	// CodeUnsupportedInput means Generate cannot build a plan for the input.
+	CodeUnsupportedInput = "unsupported_input"
+	// CodeUnknownDirective means a directive op is not recognized.
+	CodeUnknownDirective = "unknown_directive"
+	// CodeInvalidPath means a path expression is malformed or does not exist.
+	CodeInvalidPath = "invalid_path"
+	// CodeTypeMismatch means a path resolves to the wrong kind of JSON value.
+	CodeTypeMismatch = "type_mismatch"
+	// CodeMissingField means a field path does not exist.
+	CodeMissingField = "missing_field"
+	// CodeNonScalarField means a field path resolves to an object or array.
+	CodeNonScalarField = "non_scalar_field"
+	// CodeNonScalarItem means an array item is an object or array where a
+	// scalar is required.
+	CodeNonScalarItem = "non_scalar_item"
+	// CodeNonObjectItem means an array item is not an object where an object
+	// is required.
+	CodeNonObjectItem = "non_object_item"
+	// CodeNonNumericValue means an aggregate found a non-numeric value.
+	CodeNonNumericValue = "non_numeric_value"
+	// CodeNumberOutOfRange means an aggregate found a number whose exponent is
+	// too large to compute with exactly.
+	CodeNumberOutOfRange = "number_out_of_range"
+	// CodeUnmappedValue means a value has no entry in a directive's mapping.
+	CodeUnmappedValue = "unmapped_value"
+	// CodeInvalidURL means a URL is malformed or uses an unsafe scheme.
+	CodeInvalidURL = "invalid_url"
+	// CodeMissingCoverage means the plan does not render a scalar value.
+	CodeMissingCoverage = "missing_coverage"
+	// CodeInputTooLarge means the JSON input or the plan exceeds
+	// InputLimits.MaxInputBytes.
+	CodeInputTooLarge = "input_too_large"
+	// CodeNestingTooDeep means the JSON input exceeds InputLimits.MaxDepth.
+	CodeNestingTooDeep = "nesting_too_deep"
+	// CodeTooManyDirectives means the plan exceeds
+	// RenderOptions.MaxDirectives.
+	CodeTooManyDirectives = "too_many_directives"
+	// CodeOutputTooLarge means the rendered output exceeds
+	// RenderOptions.MaxOutputBytes, or Block Kit output has more blocks than
+	// Slack accepts in one message.
+	CodeOutputTooLarge = "output_too_large"
+	// CodeUnsupportedFormat means RenderOptions selects an output format that
+	// does not exist or does not support the requested options.
+	CodeUnsupportedFormat = "unsupported_format"
+)
+
+// Error describes why a plan could not be generated, validated, or rendered.
+type Error struct {
+	// Code identifies the kind of failure.
+	Code string
+	// Directive is the index of the failing directive in the plan, or -1 when
+	// the failure is not tied to a directive.
+	Directive int
+	// Path is the JSON path or plan path involved, if any.
+	Path string
+	// Message is a human-readable description of the failure.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// wrapError converts err to an *Error, using code when err carries no code of
+// its own. Context errors are returned as is.
+func wrapError(err error, code string) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var diagnostic *diagnostics.Error
+	if errors.As(err, &diagnostic) {
+		return &Error{
+			Code:      diagnostic.Code,
+			Directive: diagnostic.Directive,
+			Path:      diagnostic.Path,
+			Message:   diagnostic.Message,
+		}
+	}
+
+	return &Error{
+		Code:      code,
+		Directive: -1,
+		Message:   err.Error(),
+	}
+}
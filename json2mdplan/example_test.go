@@ -0,0 +1,187 @@
+package json2mdplan_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+func ExampleRender() {
+	input := []byte(`{"name":"Alice","role":"Engineer"}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {
+      "op": "named_bullets",
+      "path": ".",
+      "fields": [
+        {"path": "name", "label": "Name"},
+        {"path": "role", "label": "Role"}
+      ]
+    }
+  ]
+}`)
+
+	output, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(output)
+	// Output:
+	// - **Name:** Alice
+	// - **Role:** Engineer
+}
+
+func ExampleGenerate() {
+	input := []byte(`{"name":"Alice"}`)
+
+	plan, err := json2mdplan.Generate(context.Background(), input, json2mdplan.GenerateOptions{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(plan))
+	// Output:
+	// {
+	//   "version": 1,
+	//   "directives": [
+	//     {
+	//       "op": "named_bullets",
+	//       "path": ".",
+	//       "fields": [
+	//         {
+	//           "path": "name",
+	//           "label": "name"
+	//         }
+	//       ]
+	//     }
+	//   ]
+	// }
+}
+
+func ExampleError() {
+	input := []byte(`{"name":"Alice","role":"Engineer"}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {
+      "op": "named_bullets",
+      "path": ".",
+      "fields": [
+        {"path": "name", "label": "Name"}
+      ]
+    }
+  ]
+}`)
+
+	err := json2mdplan.Validate(context.Background(), input, plan, json2mdplan.RenderOptions{})
+
+	var planErr *json2mdplan.Error
+	if errors.As(err, &planErr) && planErr.Code == json2mdplan.CodeMissingCoverage {
+		fmt.Println(planErr.Path)
+	}
+	// Output:
+	// /role
+}
+
+func ExampleRegister() {
+	// checklist renders an array of tasks as a Markdown task list, ticking
+	// tasks whose member named by the "done" option is true.
+	err := json2mdplan.Register("checklist", []string{"done"}, json2mdplan.HandlerFunc(
+		func(ctx context.Context, root *json2mdplan.Value, directive json2mdplan.Directive) (*json2mdplan.Result, error) {
+			done := "done"
+			if _, err := directive.Option("done", &done); err != nil {
+				return nil, err
+			}
+
+			tasks, err := root.Get(directive.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			result := &json2mdplan.Result{}
+			for _, task := range tasks.Items() {
+				title, err := task.Get("title")
+				if err != nil {
+					return nil, err
+				}
+				finished, err := task.Get(done)
+				if err != nil {
+					return nil, err
+				}
+
+				box := " "
+				if finished.Text() == "true" {
+					box = "x"
+				}
+				result.Lines = append(result.Lines, fmt.Sprintf("- [%s] %s", box, title.Text()))
+				result.Consumed = append(result.Consumed, title.Pointer(), finished.Pointer())
+			}
+			return result, nil
+		},
+	))
+	if err != nil {
+		panic(err)
+	}
+
+	input := []byte(`[{"finished":true,"title":"Write docs"},{"finished":false,"title":"Ship"}]`)
+	plan := []byte(`{"version":1,"directives":[{"op":"checklist","path":".","done":"finished"}]}`)
+
+	output, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(output)
+
+	unknown := []byte(`{"version":1,"directives":[{"op":"checklist","path":".","color":"red"}]}`)
+	_, err = json2mdplan.Render(context.Background(), input, unknown, json2mdplan.RenderOptions{})
+	fmt.Println(err)
+	// Output:
+	// - [x] Write docs
+	// - [ ] Ship
+	// json: unknown field "color"
+}
+
+func ExampleRenderTo() {
+	input := []byte(`{"name":"Alice","role":"Engineer"}`)
+	plan := []byte(`{"version":1,"directives":[{"op":"named_bullets","path":".","fields":[{"path":"name","label":"Name"}]}]}`)
+
+	// The plan does not cover /role, so nothing is written to the output.
+	var output strings.Builder
+	err := json2mdplan.RenderTo(context.Background(), &output, input, plan, json2mdplan.RenderOptions{SpoolDir: os.TempDir()})
+	fmt.Printf("%q\n%v\n", output.String(), err)
+	// Output:
+	// ""
+	// plan does not cover JSON path "/role"
+}
+
+func ExampleRenderDocument() {
+	input := []byte(`{"title":"Release notes","changes":["Faster tables","Fewer bugs"]}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "paragraph", "path": "title"},
+    {"op": "bullet_list", "path": "changes"}
+  ]
+}`)
+
+	doc, err := json2mdplan.RenderDocument(context.Background(), input, plan, json2mdplan.RenderOptions{})
+	if err != nil {
+		panic(err)
+	}
+
+	// Promote the opening paragraph to a heading before writing Markdown.
+	if paragraph, ok := doc.Blocks[0].(*ast.Paragraph); ok {
+		doc.Blocks[0] = &ast.Heading{Level: 1, Inlines: paragraph.Inlines}
+	}
+	fmt.Println(json2mdplan.Markdown(doc))
+	// Output:
+	// # Release notes
+	//
+	// - Faster tables
+	// - Fewer bugs
+}
@@ -0,0 +1,79 @@
+package json2mdplan
+
+import (
+	"fmt"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/asciidoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/engine"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/html"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jira"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/markdown"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plaintext"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/slack"
+)
+
+// Format selects the output format of Render and RenderTo.
+type Format string
+
+const (
+	// FormatMarkdown is GitHub-flavored Markdown. It is the default.
+	FormatMarkdown Format = "markdown"
+	// FormatHTML is sanitized HTML with every value escaped. It is an HTML
+	// fragment unless RenderOptions.Standalone is set.
+	FormatHTML Format = "html"
+	// FormatSlack is Slack mrkdwn, with tables written as aligned code blocks.
+	FormatSlack Format = "slack"
+	// FormatBlockKit is Slack Block Kit JSON, an object with a "blocks" array
+	// that can be posted with chat.postMessage.
+	FormatBlockKit Format = "blockkit"
+	// FormatJira is Jira and Confluence wiki markup.
+	FormatJira Format = "jira"
+	// FormatAsciiDoc is AsciiDoc, with front matter written as document
+	// attributes.
+	FormatAsciiDoc Format = "asciidoc"
+	// FormatText is plain text for terminals and logs, with underlined
+	// headings and tables aligned by display width.
+	FormatText Format = "text"
+)
+
+// engineFormat returns the serializer for the selected output format.
+func (opts RenderOptions) engineFormat() (engine.Format, error) {
+	if opts.Format == FormatHTML {
+		return func() engine.Serializer { return html.New(opts.Standalone) }, nil
+	}
+
+	var format engine.Format
+	switch opts.Format {
+	case "", FormatMarkdown:
+		format = func() engine.Serializer { return markdown.Serializer{} }
+	case FormatSlack:
+		format = func() engine.Serializer { return slack.Serializer{} }
+	case FormatBlockKit:
+		format = func() engine.Serializer { return &slack.BlockKit{} }
+	case FormatJira:
+		format = func() engine.Serializer { return jira.Serializer{} }
+	case FormatAsciiDoc:
+		format = func() engine.Serializer { return asciidoc.Serializer{} }
+	case FormatText:
+		format = func() engine.Serializer { return plaintext.Serializer{} }
+	default:
+		return nil, unsupportedFormat("output format %q is not supported", opts.Format)
+	}
+
+	if opts.Standalone {
+		name := opts.Format
+		if name == "" {
+			name = FormatMarkdown
+		}
+		return nil, unsupportedFormat("standalone output is not supported for format %q", name)
+	}
+	return format, nil
+}
+
+func unsupportedFormat(format string, args ...any) error {
+	return &Error{
+		Code:      CodeUnsupportedFormat,
+		Directive: -1,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,206 @@
+// Package json2mdplan renders Markdown, or another output format, from a JSON
+// document and a rendering plan, and generates baseline plans from JSON. It
+// is the supported way to embed json2mdplan in a Go program; the packages
+// under internal/ may change without notice.
+//
+// # Compatibility
+//
+// A plan declares its format in its "version" member. Every plan whose version
+// is PlanVersion that renders successfully with one release of this module
+// renders to the same Markdown with every later release that has the same
+// major module version. New directives and options may be added, so a plan
+// written for a newer release may be rejected by an older one. A change that
+// would alter the output of an existing plan is made only under a new
+// PlanVersion.
+package json2mdplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/engine"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/plan"
+)
+
+// PlanVersion is the plan format version that this release reads and that
+// Generate writes.
+const PlanVersion = plan.Version
+
+// RenderOptions configures Render, RenderTo, and Validate. The zero value
+// selects the default behavior.
+type RenderOptions struct {
+	// Format selects the output format. The zero value selects Markdown.
+	Format Format
+	// Standalone wraps HTML output in a complete document with a minimal
+	// stylesheet. It is only supported with FormatHTML.
+	Standalone bool
+
+	// SpoolDir, when set, makes RenderTo hold output in a temporary file in
+	// this directory, rather than in memory, until the plan is known to cover
+	// the input. The file is removed before RenderTo returns.
+	SpoolDir string
+
+	// Report, when set, is filled in when Render, RenderTo, RenderDocument, or
+	// Validate succeeds.
+	Report *Report
+
+	// InputLimits bounds the JSON input and the plan.
+	InputLimits
+	// MaxDirectives limits the number of directives in the plan, including
+	// directives nested in details. Zero means no limit.
+	MaxDirectives int
+	// MaxOutputBytes limits the size of the rendered output. Zero means no
+	// limit.
+	MaxOutputBytes int
+}
+
+// Report describes how a plan covered the JSON input.
+type Report struct {
+	// Elided lists, in document order, the JSON Pointers of the scalar values
+	// that the plan deliberately left out, such as array items outside a
+	// limit window. Every other scalar value was rendered.
+	Elided []string
+}
+
+// GenerateOptions configures Generate. The zero value selects the default
+// behavior.
+type GenerateOptions struct {
+	// InputLimits bounds the JSON input.
+	InputLimits
+}
+
+// InputLimits bounds the documents read by Render, RenderTo, Validate, and
+// Generate. A zero field means no limit.
+type InputLimits struct {
+	// MaxInputBytes limits the size of the JSON input and, separately, of the
+	// plan.
+	MaxInputBytes int
+	// MaxDepth limits how deeply objects and arrays may be nested in the JSON
+	// input.
+	MaxDepth int
+}
+
+// Render renders the JSON document with the plan and returns the output in
+// the selected format. Nothing is returned unless the plan is valid and covers
+// every scalar value in the document. Errors are of type *Error, except that
+// the context error is returned as is when ctx is done.
+func Render(ctx context.Context, jsonBytes []byte, planBytes []byte, opts RenderOptions) (string, error) {
+	format, err := opts.engineFormat()
+	if err != nil {
+		return "", err
+	}
+	root, parsedPlan, err := parseInputs(ctx, jsonBytes, planBytes, opts.InputLimits)
+	if err != nil {
+		return "", err
+	}
+
+	evaluation, err := engine.Evaluate(ctx, root, parsedPlan, opts.engineLimits(), format)
+	if err != nil {
+		return "", wrapError(err, CodeError)
+	}
+	opts.report(evaluation.Elided)
+	return strings.Join(evaluation.Lines, "\n"), nil
+}
+
+// Validate reports whether the plan renders the JSON document, returning the
+// error that Render would return, without producing any output.
+func Validate(ctx context.Context, jsonBytes []byte, planBytes []byte, opts RenderOptions) error {
+	format, err := opts.engineFormat()
+	if err != nil {
+		return err
+	}
+	root, parsedPlan, err := parseInputs(ctx, jsonBytes, planBytes, opts.InputLimits)
+	if err != nil {
+		return err
+	}
+
+	evaluation, err := engine.Evaluate(ctx, root, parsedPlan, opts.engineLimits(), format)
+	if err != nil {
+		return wrapError(err, CodeError)
+	}
+	opts.report(evaluation.Elided)
+	return nil
+}
+
+// Generate returns a baseline plan for the JSON document, encoded as indented
+// JSON exactly as the plan command writes it.
+func Generate(ctx context.Context, jsonBytes []byte, opts GenerateOptions) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	root, err := opts.parseJSON(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	generatedPlan, err := plan.Generate(root)
+	if err != nil {
+		return nil, wrapError(err, CodeUnsupportedInput)
+	}
+
+	output, err := plan.Marshal(*generatedPlan)
+	if err != nil {
+		return nil, wrapError(err, CodeError)
+	}
+	return output, nil
+}
+
+func (opts RenderOptions) engineLimits() engine.Limits {
+	return engine.Limits{
+		MaxDirectives:  opts.MaxDirectives,
+		MaxOutputBytes: opts.MaxOutputBytes,
+	}
+}
+
+// report fills in opts.Report, when set, with the elided paths.
+func (opts RenderOptions) report(elided []string) {
+	if opts.Report != nil {
+		*opts.Report = Report{Elided: elided}
+	}
+}
+
+// parseJSON parses the JSON input within the limits.
+func (limits InputLimits) parseJSON(jsonBytes []byte) (*jsondoc.Node, error) {
+	if limits.MaxInputBytes > 0 && len(jsonBytes) > limits.MaxInputBytes {
+		return nil, &Error{
+			Code:      CodeInputTooLarge,
+			Directive: -1,
+			Message:   fmt.Sprintf("JSON input exceeds %d bytes", limits.MaxInputBytes),
+		}
+	}
+
+	root, err := jsondoc.ParseLimited(jsonBytes, limits.MaxDepth)
+	if err != nil {
+		return nil, wrapError(err, CodeInvalidJSON)
+	}
+	return root, nil
+}
+
+func parseInputs(ctx context.Context, jsonBytes []byte, planBytes []byte, limits InputLimits) (*jsondoc.Node, *plan.Plan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	root, err := limits.parseJSON(jsonBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if limits.MaxInputBytes > 0 && len(planBytes) > limits.MaxInputBytes {
+		return nil, nil, &Error{
+			Code:      CodeInputTooLarge,
+			Directive: -1,
+			Message:   fmt.Sprintf("plan exceeds %d bytes", limits.MaxInputBytes),
+		}
+	}
+
+	parsedPlan, err := plan.Parse(planBytes)
+	if err != nil {
+		return nil, nil, wrapError(err, CodeInvalidPlan)
+	}
+
+	return root, parsedPlan, ctx.Err()
+}
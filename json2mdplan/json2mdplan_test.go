@@ -0,0 +1,346 @@
+package json2mdplan_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan"
+	"github.com/UnitVectorY-Labs/json2mdplan/json2mdplan/ast"
+)
+
+func TestRenderLimits(t *testing.T) {
+	input := []byte(`{"tags":["go","cli"],"meta":{"owner":{"name":"Alice"}}}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "bullet_list", "path": "tags"},
+    {"op": "details", "summary": "Meta", "directives": [
+      {"op": "nested_bullets", "path": "meta"}
+    ]}
+  ]
+}`)
+
+	cases := []struct {
+		name string
+		opts json2mdplan.RenderOptions
+		code string
+	}{
+		{"within limits", json2mdplan.RenderOptions{InputLimits: json2mdplan.InputLimits{MaxInputBytes: 1024, MaxDepth: 3}, MaxDirectives: 3, MaxOutputBytes: 1024}, ""},
+		{"input bytes", json2mdplan.RenderOptions{InputLimits: json2mdplan.InputLimits{MaxInputBytes: 32}}, json2mdplan.CodeInputTooLarge},
+		{"depth", json2mdplan.RenderOptions{InputLimits: json2mdplan.InputLimits{MaxDepth: 2}}, json2mdplan.CodeNestingTooDeep},
+		{"directives", json2mdplan.RenderOptions{MaxDirectives: 2}, json2mdplan.CodeTooManyDirectives},
+		{"output bytes", json2mdplan.RenderOptions{MaxOutputBytes: 20}, json2mdplan.CodeOutputTooLarge},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := json2mdplan.Render(context.Background(), input, plan, tc.opts)
+			if tc.code == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var planErr *json2mdplan.Error
+			if !errors.As(err, &planErr) || planErr.Code != tc.code {
+				t.Fatalf("expected code %q, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestRenderReportsElided(t *testing.T) {
+	input := []byte(`{"tags":["go","cli","json"],"owner":"Alice"}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "bullet_list", "path": "tags", "limit": 1, "offset": 1},
+    {"op": "paragraph", "path": "owner"}
+  ]
+}`)
+
+	want := []string{"/tags/0", "/tags/2"}
+	check := func(t *testing.T, report json2mdplan.Report, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(report.Elided, want) {
+			t.Fatalf("expected elided paths %q, got %q", want, report.Elided)
+		}
+	}
+
+	t.Run("render", func(t *testing.T) {
+		var report json2mdplan.Report
+		_, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{Report: &report})
+		check(t, report, err)
+	})
+	t.Run("render to", func(t *testing.T) {
+		var report json2mdplan.Report
+		err := json2mdplan.RenderTo(context.Background(), io.Discard, input, plan, json2mdplan.RenderOptions{Format: json2mdplan.FormatText, Report: &report})
+		check(t, report, err)
+	})
+	t.Run("validate", func(t *testing.T) {
+		var report json2mdplan.Report
+		err := json2mdplan.Validate(context.Background(), input, plan, json2mdplan.RenderOptions{Report: &report})
+		check(t, report, err)
+	})
+	t.Run("nothing elided", func(t *testing.T) {
+		report := json2mdplan.Report{Elided: []string{"/stale"}}
+		plan := []byte(`{"version":1,"directives":[{"op":"bullet_list","path":"tags"},{"op":"paragraph","path":"owner"}]}`)
+		_, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{Report: &report})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Elided) != 0 {
+			t.Fatalf("expected no elided paths, got %q", report.Elided)
+		}
+	})
+}
+
+func TestRenderToTableOfContents(t *testing.T) {
+	input := []byte(`{"name":"Alice","notes":"Joined in 2020."}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "toc"},
+    {"op": "text", "text": "# Profile", "markdown": true},
+    {"op": "paragraph", "path": "name"},
+    {"op": "text", "text": "Notes\n-----", "markdown": true},
+    {"op": "paragraph", "path": "notes"}
+  ]
+}`)
+
+	want, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(want, "- [Profile](#profile)\n  - [Notes](#notes)\n") {
+		t.Fatalf("expected the table of contents to list the headings after it:\n%s", want)
+	}
+
+	for _, spoolDir := range []string{"", t.TempDir()} {
+		var out strings.Builder
+		err := json2mdplan.RenderTo(context.Background(), &out, input, plan, json2mdplan.RenderOptions{SpoolDir: spoolDir})
+		if err != nil {
+			t.Fatalf("unexpected error with spool dir %q: %v", spoolDir, err)
+		}
+		if out.String() != want {
+			t.Fatalf("expected RenderTo with spool dir %q to match Render:\n%s\ngot:\n%s", spoolDir, want, out.String())
+		}
+		if spoolDir != "" {
+			if entries, err := os.ReadDir(spoolDir); err != nil || len(entries) != 0 {
+				t.Fatalf("expected the spool file to be removed, got %v, %v", entries, err)
+			}
+		}
+	}
+}
+
+func TestRenderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := json2mdplan.Render(ctx, []byte(`{}`), []byte(`{"version":1,"directives":[]}`), json2mdplan.RenderOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRenderStandaloneHTML(t *testing.T) {
+	input := []byte(`{"title":"Q3 <report>","summary":"Revenue & costs"}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "front_matter", "path": ".", "fields": [{"path": "title", "label": "title"}]},
+    {"op": "paragraph", "path": "summary"}
+  ]
+}`)
+
+	output, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{Format: json2mdplan.FormatHTML, Standalone: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"<!DOCTYPE html>", "<title>Q3 &lt;report&gt;</title>", "<style>", "<p>Revenue &amp; costs</p>"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected output to contain %q:\n%s", want, output)
+		}
+	}
+	if !strings.HasSuffix(output, "</body>\n</html>") {
+		t.Fatalf("expected output to end the document:\n%s", output)
+	}
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	input := []byte(`{}`)
+	plan := []byte(`{"version":1,"directives":[]}`)
+
+	cases := []struct {
+		name string
+		opts json2mdplan.RenderOptions
+	}{
+		{"unknown format", json2mdplan.RenderOptions{Format: "rtf"}},
+		{"standalone markdown", json2mdplan.RenderOptions{Standalone: true}},
+		{"standalone asciidoc", json2mdplan.RenderOptions{Format: json2mdplan.FormatAsciiDoc, Standalone: true}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := json2mdplan.Render(context.Background(), input, plan, tc.opts)
+
+			var planErr *json2mdplan.Error
+			if !errors.As(err, &planErr) || planErr.Code != json2mdplan.CodeUnsupportedFormat {
+				t.Fatalf("expected code %q, got %v", json2mdplan.CodeUnsupportedFormat, err)
+			}
+		})
+	}
+}
+
+func TestRenderInvalidCustomBlocks(t *testing.T) {
+	cases := []struct {
+		op     string
+		blocks []ast.Block
+	}{
+		{"test_nil_block", []ast.Block{nil}},
+		{"test_nil_pointer", []ast.Block{(*ast.Paragraph)(nil)}},
+		{"test_nil_inline", []ast.Block{&ast.Paragraph{Inlines: []ast.Inline{nil}}}},
+		{"test_nested_nil", []ast.Block{&ast.List{Items: []*ast.ListItem{{Blocks: []ast.Block{nil}}}}}},
+		{"test_heading_level", []ast.Block{&ast.Heading{Level: 7}}},
+		{"test_alert_kind", []ast.Block{&ast.Alert{Kind: "note"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.op, func(t *testing.T) {
+			err := json2mdplan.Register(tc.op, nil, json2mdplan.HandlerFunc(
+				func(ctx context.Context, root *json2mdplan.Value, directive json2mdplan.Directive) (*json2mdplan.Result, error) {
+					return &json2mdplan.Result{Blocks: tc.blocks}, nil
+				}))
+			if err != nil {
+				t.Fatalf("register: %v", err)
+			}
+
+			plan := []byte(`{"version":1,"directives":[{"op":"` + tc.op + `","path":"."}]}`)
+			_, err = json2mdplan.Render(context.Background(), []byte(`{}`), plan, json2mdplan.RenderOptions{})
+
+			var planErr *json2mdplan.Error
+			if !errors.As(err, &planErr) || planErr.Code != json2mdplan.CodeInvalidPlan || planErr.Directive != 0 {
+				t.Fatalf("expected code %q for directive 0, got %v", json2mdplan.CodeInvalidPlan, err)
+			}
+			if !strings.Contains(planErr.Message, tc.op) {
+				t.Fatalf("expected message to name %q, got %q", tc.op, planErr.Message)
+			}
+		})
+	}
+}
+
+func TestRenderToWritesNothingOnFailure(t *testing.T) {
+	input := []byte(`{"title":"Release","tags":["go","cli"],"owner":"Alice"}`)
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "paragraph", "path": "title"},
+    {"op": "bullet_list", "path": "tags"}
+  ]
+}`)
+
+	for _, spoolDir := range []string{"", t.TempDir()} {
+		var output bytes.Buffer
+		err := json2mdplan.RenderTo(context.Background(), &output, input, plan, json2mdplan.RenderOptions{SpoolDir: spoolDir})
+
+		var planErr *json2mdplan.Error
+		if !errors.As(err, &planErr) || planErr.Code != json2mdplan.CodeMissingCoverage {
+			t.Fatalf("spool dir %q: expected code %q, got %v", spoolDir, json2mdplan.CodeMissingCoverage, err)
+		}
+		if output.Len() != 0 {
+			t.Fatalf("spool dir %q: expected no output, got %q", spoolDir, output.String())
+		}
+		if spoolDir != "" {
+			if entries, _ := os.ReadDir(spoolDir); len(entries) != 0 {
+				t.Fatalf("expected the spool file to be removed, found %d entries", len(entries))
+			}
+		}
+	}
+}
+
+func TestRenderToSpoolDirError(t *testing.T) {
+	spoolDir := filepath.Join(t.TempDir(), "missing")
+
+	var output bytes.Buffer
+	err := json2mdplan.RenderTo(context.Background(), &output, []byte(`{}`), []byte(`{"version":1,"directives":[]}`), json2mdplan.RenderOptions{SpoolDir: spoolDir})
+
+	var planErr *json2mdplan.Error
+	if !errors.As(err, &planErr) || planErr.Code != json2mdplan.CodeError {
+		t.Fatalf("expected code %q, got %v", json2mdplan.CodeError, err)
+	}
+}
+
+func TestRenderBlockKitSplitsSections(t *testing.T) {
+	code := strings.Repeat("if a < b && c > d {}\n", 200)
+	prose := strings.Repeat("Fish & chips <https://example.com|menu> ", 100)
+	input, err := json.Marshal(map[string]string{"code": code, "prose": prose})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := []byte(`{
+  "version": 1,
+  "directives": [
+    {"op": "code_block", "path": "code"},
+    {"op": "paragraph", "path": "prose"}
+  ]
+}`)
+
+	output, err := json2mdplan.Render(context.Background(), input, plan, json2mdplan.RenderOptions{Format: json2mdplan.FormatBlockKit})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var message struct {
+		Blocks []struct {
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(output), &message); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(message.Blocks) < 3 {
+		t.Fatalf("expected the long blocks to be split, got %d sections", len(message.Blocks))
+	}
+	for _, block := range message.Blocks {
+		text := block.Text.Text
+		if n := len([]rune(text)); n > 3000 {
+			t.Fatalf("section has %d characters", n)
+		}
+		if n := strings.Count(text, "```"); n%2 != 0 {
+			t.Fatalf("section has an unclosed code block:\n%s", text)
+		}
+		if strings.Count(text, "&") != strings.Count(text, ";") || strings.Count(text, "<") != strings.Count(text, ">") {
+			t.Fatalf("section splits an escape or a link:\n%s", text)
+		}
+	}
+}
+
+func TestRenderBlockKitTooManyBlocks(t *testing.T) {
+	directives := make([]string, 0, 51)
+	for range 51 {
+		directives = append(directives, `{"op": "rule"}`)
+	}
+	plan := []byte(`{"version": 1, "directives": [` + strings.Join(directives, ",") + `]}`)
+
+	_, err := json2mdplan.Render(context.Background(), []byte(`{}`), plan, json2mdplan.RenderOptions{Format: json2mdplan.FormatBlockKit})
+
+	var planErr *json2mdplan.Error
+	if !errors.As(err, &planErr) || planErr.Code != json2mdplan.CodeOutputTooLarge {
+		t.Fatalf("expected code %q, got %v", json2mdplan.CodeOutputTooLarge, err)
+	}
+}
@@ -0,0 +1,102 @@
+package json2mdplan
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/engine"
+)
+
+// RenderTo renders the JSON document with the plan and writes the output in
+// the selected format to w. Each directive's output is written to a spool as
+// soon as it is rendered, and the spool is copied to w only once every
+// directive has run and the plan is known to cover the input, so w receives
+// nothing when rendering fails.
+// The spool is held in memory unless opts.SpoolDir is set.
+//
+// Errors are reported as by Render. An error writing to w is returned as is
+// and may leave w with partial output.
+func RenderTo(ctx context.Context, w io.Writer, jsonBytes []byte, planBytes []byte, opts RenderOptions) error {
+	format, err := opts.engineFormat()
+	if err != nil {
+		return err
+	}
+	root, parsedPlan, err := parseInputs(ctx, jsonBytes, planBytes, opts.InputLimits)
+	if err != nil {
+		return err
+	}
+
+	spool, err := newSpool(opts.SpoolDir)
+	if err != nil {
+		return wrapError(err, CodeError)
+	}
+	defer spool.Close()
+
+	elided, err := engine.Stream(ctx, root, parsedPlan, opts.engineLimits(), format, spool)
+	if err != nil {
+		return wrapError(err, CodeError)
+	}
+	if err := spool.commit(w); err != nil {
+		return err
+	}
+	opts.report(elided)
+	return nil
+}
+
+// spool holds rendered output until it is committed to the destination.
+type spool interface {
+	io.WriteCloser
+	commit(w io.Writer) error
+}
+
+func newSpool(dir string) (spool, error) {
+	if dir == "" {
+		return &memorySpool{}, nil
+	}
+
+	file, err := os.CreateTemp(dir, "json2mdplan-*.md")
+	if err != nil {
+		return nil, err
+	}
+	return &fileSpool{file: file}, nil
+}
+
+type memorySpool struct {
+	bytes.Buffer
+}
+
+func (s *memorySpool) commit(w io.Writer) error {
+	_, err := s.WriteTo(w)
+	return err
+}
+
+func (s *memorySpool) Close() error {
+	return nil
+}
+
+type fileSpool struct {
+	file *os.File
+}
+
+func (s *fileSpool) Write(p []byte) (int, error) {
+	return s.file.Write(p)
+}
+
+func (s *fileSpool) commit(w io.Writer) error {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, s.file)
+	return err
+}
+
+// Close removes the spool file, whether or not it was committed.
+func (s *fileSpool) Close() error {
+	closeErr := s.file.Close()
+	if err := os.Remove(s.file.Name()); err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,151 @@
+package json2mdplan
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/UnitVectorY-Labs/json2mdplan/internal/jsondoc"
+)
+
+// Kind is the JSON type of a Value.
+type Kind string
+
+const (
+	KindObject  Kind = Kind(jsondoc.Object)
+	KindArray   Kind = Kind(jsondoc.Array)
+	KindString  Kind = Kind(jsondoc.String)
+	KindNumber  Kind = Kind(jsondoc.Number)
+	KindBoolean Kind = Kind(jsondoc.Boolean)
+	KindNull    Kind = Kind(jsondoc.Null)
+)
+
+// Value is a read-only view of one value in the JSON input, identified by its
+// JSON Pointer. Object members and array items keep their source order.
+type Value struct {
+	root    *jsondoc.Node
+	node    *jsondoc.Node
+	pointer string
+}
+
+// Member is one member of a JSON object.
+type Member struct {
+	Name  string
+	Value *Value
+}
+
+// Kind returns the JSON type of the value.
+func (v *Value) Kind() Kind {
+	return Kind(v.node.Kind)
+}
+
+// Pointer returns the JSON Pointer of the value, which is what a handler
+// reports in Result.Consumed once the value is rendered. The document root
+// has the empty pointer.
+func (v *Value) Pointer() string {
+	return v.pointer
+}
+
+// IsScalar reports whether the value is a string, number, boolean, or null.
+func (v *Value) IsScalar() bool {
+	return v.node.IsScalar()
+}
+
+// Text returns a scalar value as text, with numbers in their original JSON
+// form and null as "null". It returns an empty string for objects and arrays.
+func (v *Value) Text() string {
+	text, err := v.node.FormatScalar()
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// Len returns the number of members of an object or items of an array, and
+// zero for scalars.
+func (v *Value) Len() int {
+	switch v.node.Kind {
+	case jsondoc.Object:
+		return len(v.node.Object)
+	case jsondoc.Array:
+		return len(v.node.Array)
+	default:
+		return 0
+	}
+}
+
+// Members returns the members of an object in source order, or nil when the
+// value is not an object.
+func (v *Value) Members() []Member {
+	if v.node.Kind != jsondoc.Object {
+		return nil
+	}
+
+	members := make([]Member, 0, len(v.node.Object))
+	for _, field := range v.node.Object {
+		members = append(members, Member{
+			Name:  field.Name,
+			Value: v.child(field.Value, field.Name),
+		})
+	}
+	return members
+}
+
+// Items returns the items of an array in order, or nil when the value is not
+// an array.
+func (v *Value) Items() []*Value {
+	if v.node.Kind != jsondoc.Array {
+		return nil
+	}
+
+	items := make([]*Value, 0, len(v.node.Array))
+	for index, item := range v.node.Array {
+		items = append(items, v.child(item, strconv.Itoa(index)))
+	}
+	return items
+}
+
+// Get resolves a plan path relative to the value, using the same syntax as
+// directive paths. A path starting with "/" is resolved from the document
+// root. It fails with an *Error with code CodeInvalidPath when the path does
+// not exist.
+func (v *Value) Get(path string) (*Value, error) {
+	tokens, err := jsondoc.PointerTokens(v.pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	node, pointer, err := jsondoc.Resolve(v.root, v.node, tokens, path)
+	if err != nil {
+		return nil, &Error{
+			Code:      CodeInvalidPath,
+			Directive: -1,
+			Path:      path,
+			Message:   fmt.Sprintf("path %q could not be resolved: %s", path, err.Error()),
+		}
+	}
+	return &Value{root: v.root, node: node, pointer: pointer}, nil
+}
+
+// LeafPointers returns the JSON Pointers of every scalar inside the value, or
+// of the value itself when it is a scalar.
+func (v *Value) LeafPointers() []string {
+	tokens, err := jsondoc.PointerTokens(v.pointer)
+	if err != nil {
+		return nil
+	}
+	return v.node.LeafPaths(tokens)
+}
+
+// JSON returns the value encoded as compact JSON, preserving member order and
+// the original text of numbers.
+func (v *Value) JSON() ([]byte, error) {
+	return v.node.Marshal()
+}
+
+func (v *Value) child(node *jsondoc.Node, token string) *Value {
+	return &Value{
+		root:    v.root,
+		node:    node,
+		pointer: v.pointer + jsondoc.EncodePointer([]string{token}),
+	}
+}